@@ -0,0 +1,114 @@
+package bncclient
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Balance -- balance of an asset in spot account.
+type Balance struct {
+	Asset  string  `json:"asset"`
+	Free   float64 `json:"free,string"`
+	Locked float64 `json:"locked,string"`
+}
+
+// AccountInfo -- spot account information.
+type AccountInfo struct {
+	MakerCommission            int       `json:"makerCommission"`
+	TakerCommission            int       `json:"takerCommission"`
+	BuyerCommission            int       `json:"buyerCommission"`
+	SellerCommission           int       `json:"sellerCommission"`
+	CanTrade                   bool      `json:"canTrade"`
+	CanWithdraw                bool      `json:"canWithdraw"`
+	CanDeposit                 bool      `json:"canDeposit"`
+	Brokered                   bool      `json:"brokered"`
+	RequireSelfTradePrevention bool      `json:"requireSelfTradePrevention"`
+	UpdateTime                 int64     `json:"updateTime"`
+	AccountType                string    `json:"accountType"`
+	Balances                   []Balance `json:"balances"`
+	Permissions                []string  `json:"permissions"`
+}
+
+// GetBalance returns balance of the asset, second value is false if there is no such asset in the account.
+func (ai AccountInfo) GetBalance(asset string) (Balance, bool) {
+	for _, balance := range ai.Balances {
+		if balance.Asset == asset {
+			return balance, true
+		}
+	}
+
+	return Balance{}, false
+}
+
+// accountCache -- cached copy of account information with its expiration time.
+// The mutex is never held during the request. generation is incremented on every invalidation, so response of a request
+// started before invalidation (which may contain outdated balances) is returned to its caller, but is not cached.
+type accountCache struct {
+	ttl         time.Duration // 0 means caching is disabled
+	accountInfo AccountInfo
+	expiresAt   time.Time
+	generation  uint64
+	mutex       sync.Mutex
+}
+
+// SetAccountInfoTTL - enables caching of GetAccountInfo for given time (disabled by default). Balances change mostly
+// when orders are filled, so while user data stream of the client is running (see StartUserDataStream), cache is
+// invalidated on every balance update event, and cached copy never stays outdated for long. Zero TTL disables caching.
+func (bc *BinanceClient) SetAccountInfoTTL(ttl time.Duration) {
+	bc.accountCache.mutex.Lock()
+	defer bc.accountCache.mutex.Unlock()
+
+	bc.accountCache.ttl = ttl
+	bc.accountCache.expiresAt = time.Time{}
+	bc.accountCache.generation++
+}
+
+// GetAccountInfo - current spot account information, including balances (SIGNED).
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#account-information-user_data
+// If caching is enabled (see SetAccountInfoTTL), cached copy is returned without network round trip until it expires.
+func (bc *BinanceClient) GetAccountInfo() (AccountInfo, Warning, error) {
+	cache := bc.accountCache
+
+	cache.mutex.Lock()
+	if cache.ttl > 0 && time.Now().Before(cache.expiresAt) {
+		accountInfo := cache.accountInfo
+		cache.mutex.Unlock()
+		return accountInfo, nil, nil
+	}
+	generation := cache.generation
+	cache.mutex.Unlock()
+
+	var accountInfo AccountInfo
+
+	warning, err := bc.newSignedRequest(http.MethodGet).Do("/api/v3/account", 20, &accountInfo)
+
+	if err != nil || warning != nil {
+		return AccountInfo{}, warning, err
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if cache.ttl > 0 && cache.generation == generation { // Not invalidated while the request was in flight
+		cache.accountInfo = accountInfo
+		cache.expiresAt = time.Now().Add(cache.ttl)
+	}
+
+	return accountInfo, nil, nil
+}
+
+// RefreshAccount - the same as GetAccountInfo, but ignores cached copy and always asks Binance.
+func (bc *BinanceClient) RefreshAccount() (AccountInfo, Warning, error) {
+	bc.accountCache.invalidate()
+
+	return bc.GetAccountInfo()
+}
+
+func (ac *accountCache) invalidate() {
+	ac.mutex.Lock()
+	defer ac.mutex.Unlock()
+
+	ac.expiresAt = time.Time{}
+	ac.generation++
+}
@@ -0,0 +1,73 @@
+package bncclient
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetAccountInfoInvalidationDuringFetchWins(t *testing.T) {
+	requestStarted := make(chan struct{}, 1)
+	releaseResponse := make(chan struct{})
+	requestsCount := 0
+
+	bc := NewTestnetClient("test-api-key", "test-secret-key")
+	bc.SetAccountInfoTTL(time.Minute)
+	bc.SetHTTPClient(doerFunc(func(request *http.Request) (*http.Response, error) {
+		requestsCount++
+		if requestsCount == 1 {
+			requestStarted <- struct{}{}
+			<-releaseResponse
+			return jsonResponse(request, `{"balances":[{"asset":"BTC","free":"1.0","locked":"0.0"}]}`), nil
+		}
+		return jsonResponse(request, `{"balances":[{"asset":"BTC","free":"2.0","locked":"0.0"}]}`), nil
+	}))
+
+	firstResult := make(chan AccountInfo)
+	go func() {
+		accountInfo, _, _ := bc.GetAccountInfo()
+		firstResult <- accountInfo
+	}()
+
+	<-requestStarted
+	bc.accountCache.invalidate() // Balance update arrives while the first request is in flight (cache mutex is not held)
+	close(releaseResponse)
+
+	if balance, _ := (<-firstResult).GetBalance("BTC"); balance.Free != 1.0 {
+		t.Fatalf("expected first caller to get its own response, got %v", balance.Free)
+	}
+
+	accountInfo, _, err := bc.GetAccountInfo()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if balance, _ := accountInfo.GetBalance("BTC"); balance.Free != 2.0 {
+		t.Fatalf("expected outdated response not to be cached, got free balance %v", balance.Free)
+	}
+
+	if requestsCount != 2 {
+		t.Fatalf("expected 2 requests, got %d", requestsCount)
+	}
+}
+
+func TestGetAccountInfoCachesWithinTTL(t *testing.T) {
+	requestsCount := 0
+
+	bc := NewTestnetClient("test-api-key", "test-secret-key")
+	bc.SetAccountInfoTTL(time.Minute)
+	bc.SetHTTPClient(doerFunc(func(request *http.Request) (*http.Response, error) {
+		requestsCount++
+		return jsonResponse(request, `{"balances":[]}`), nil
+	}))
+
+	for i := 0; i < 3; i++ {
+		if _, _, err := bc.GetAccountInfo(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if requestsCount != 1 {
+		t.Fatalf("expected 1 request, got %d", requestsCount)
+	}
+}
@@ -0,0 +1,385 @@
+package bncclient
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+)
+
+const aggTradesMaxLimit = 1000             // Binance returns maximum 1000 aggregated trades per request
+const DefaultAggTradesLimit = 500          // Number of aggregated trades Binance returns when limit is not specified
+const aggTradesStreamPollIntervalMS = 1000 // How long to wait before next poll, when there are no new trades
+
+// StreamAggregatedTrades - polls GetAggregatedTrades starting from the latest trade and emits every new trade to returned channel.
+// Trades channel is unbuffered, so slow consumer naturally slows down polling (backpressure).
+// When weight controller returns a Warning, stream sleeps recommended time and continues.
+// Any error is sent to errors channel and stops the stream. Both channels are closed when stream stops (ctx cancelled,
+// client closed or error).
+func (bc *BinanceClient) StreamAggregatedTrades(ctx context.Context, symbol string) (<-chan AggTrade, <-chan error) {
+	tradesCh := make(chan AggTrade)
+	errCh := make(chan error, 1)
+	ctx, cancel := bc.lifecycle.bindContext(ctx)
+
+	go func() {
+		defer cancel()
+		defer close(tradesCh)
+		defer close(errCh)
+
+		fromId := int64(-1) // First request without fromId returns the most recent trade
+
+		for ctx.Err() == nil {
+			limit := aggTradesMaxLimit
+			if fromId < 0 {
+				limit = 1
+			}
+
+			aggTrades, warning, err := bc.WithContext(ctx).GetAggregatedTrades(symbol, fromId, -1, -1, limit)
+			warning, err = splitWarning(warning, err)
+
+			if err != nil {
+				if ctx.Err() == nil { // Cancellation is not an error of the stream
+					errCh <- err
+				}
+				return
+			}
+
+			if warning != nil {
+				if sleepWithContext(ctx, warning.GetRetryAfterTimeMS()) != nil {
+					return
+				}
+				continue
+			}
+
+			for _, aggTrade := range aggTrades {
+				select {
+				case tradesCh <- aggTrade:
+					fromId = aggTrade.AggTradeId + 1
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if len(aggTrades) < limit || limit == 1 {
+				if sleepWithContext(ctx, aggTradesStreamPollIntervalMS) != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	return tradesCh, errCh
+}
+
+// AggTradesDeduplicator filters out aggregated trades which were already emitted in previous pages.
+// When paginating by time, boundary records (with the same AggTime) often appear in two consecutive pages.
+// Several trades can share the same timestamp, so deduplication is keyed on AggTradeId, not on AggTime.
+// Pages should be passed in ascending order (as Binance returns them).
+type AggTradesDeduplicator struct {
+	lastEmittedId int64
+	started       bool
+}
+
+// Filter returns only trades with AggTradeId greater than the last emitted one and remembers the new last emitted id.
+func (d *AggTradesDeduplicator) Filter(page AggTradesList) AggTradesList {
+	filtered := make(AggTradesList, 0, len(page))
+
+	for _, aggTrade := range page {
+		if d.started && aggTrade.AggTradeId <= d.lastEmittedId {
+			continue
+		}
+
+		filtered = append(filtered, aggTrade)
+		d.lastEmittedId = aggTrade.AggTradeId
+		d.started = true
+	}
+
+	return filtered
+}
+
+// LastEmittedId returns AggTradeId of the last emitted trade, or -1 if nothing was emitted yet.
+func (d *AggTradesDeduplicator) LastEmittedId() int64 {
+	if !d.started {
+		return -1
+	}
+
+	return d.lastEmittedId
+}
+
+// GetAggregatedTradesPage - gets page of aggregated trades starting from fromId and returns cursor for the next page
+// (AggTradeId of the last trade + 1). When the page is shorter than limit (i.e. there are no more trades at the moment),
+// returned nextFromId is -1. Parameters fromId and limit are optional, set them to -1 if you don't want to specify them
+// (without limit Binance returns up to DefaultAggTradesLimit trades).
+func (bc *BinanceClient) GetAggregatedTradesPage(symbol string, fromId int64, limit int) (AggTradesList, int64, Warning, error) {
+	aggTrades, warning, err := bc.GetAggregatedTrades(symbol, fromId, -1, -1, limit)
+
+	if err != nil || warning != nil {
+		return nil, -1, warning, err
+	}
+
+	effectiveLimit := limit
+	if effectiveLimit < 0 {
+		effectiveLimit = DefaultAggTradesLimit
+	}
+
+	if len(aggTrades) == 0 || len(aggTrades) < effectiveLimit {
+		return aggTrades, -1, nil, nil
+	}
+
+	return aggTrades, aggTrades[len(aggTrades)-1].AggTradeId + 1, nil, nil
+}
+
+// GetAggregatedTradesByTimeRange - gets all aggregated trades between startMS and endMS (both inclusive). The range is
+// split into windows of maximum 1 hour (Binance limit), windows with more than 1000 trades are paginated by fromId.
+// Returned trades are deduplicated by AggTradeId and sorted by time (and by id within the same millisecond).
+// Weight of one request per window is reserved before the first request, so if the whole range doesn't fit into
+// the current weight window, Warning is returned right away, before anything is fetched. Additional requests (for windows
+// with more than 1000 trades) are accounted one by one. If weight limit is reached in the middle, trades fetched so far
+// are returned together with Warning, so the caller can sleep and continue from the last returned trade's AggTime.
+func (bc *BinanceClient) GetAggregatedTradesByTimeRange(symbol string, startMS int64, endMS int64) (AggTradesList, Warning, error) {
+	if startMS > endMS {
+		return nil, nil, errors.New("startMS should not be greater than endMS")
+	}
+
+	windowsCount := (endMS-startMS)/aggTradesMaxTimeWindowMS + 1
+	if windowsCount > math.MaxInt32/aggTradesWeight {
+		windowsCount = math.MaxInt32 / aggTradesWeight // Too long range can't be reserved anyway
+	}
+
+	reservedClient, warning := bc.withWeightReservation(int(windowsCount) * aggTradesWeight)
+	if warning != nil {
+		if bc.warningsAsErrors {
+			return nil, nil, warning
+		}
+		return nil, warning, nil
+	}
+
+	tradesById := make(map[int64]AggTrade)
+
+	for windowStartMS := startMS; windowStartMS <= endMS; windowStartMS += aggTradesMaxTimeWindowMS {
+		windowEndMS := windowStartMS + aggTradesMaxTimeWindowMS - 1
+		if windowEndMS > endMS {
+			windowEndMS = endMS
+		}
+
+		page, warning, err := reservedClient.GetAggregatedTrades(symbol, -1, windowStartMS, windowEndMS, aggTradesMaxLimit)
+
+		for err == nil && warning == nil {
+			pageEndReached := false
+			for _, aggTrade := range page {
+				if bc.timestampToMS(aggTrade.AggTime) > windowEndMS {
+					pageEndReached = true
+					break
+				}
+				tradesById[aggTrade.AggTradeId] = aggTrade
+			}
+
+			if pageEndReached || len(page) < aggTradesMaxLimit {
+				break
+			}
+
+			// Window has more than 1000 trades, the rest of them is paginated by id:
+			page, warning, err = reservedClient.GetAggregatedTrades(symbol, page[len(page)-1].AggTradeId+1, -1, -1, aggTradesMaxLimit)
+		}
+
+		if err != nil || warning != nil {
+			return sortAggTrades(tradesById), warning, err
+		}
+	}
+
+	return sortAggTrades(tradesById), nil, nil
+}
+
+func sortAggTrades(tradesById map[int64]AggTrade) AggTradesList {
+	aggTrades := make(AggTradesList, 0, len(tradesById))
+	for _, aggTrade := range tradesById {
+		aggTrades = append(aggTrades, aggTrade)
+	}
+
+	sort.Slice(aggTrades, func(i, j int) bool {
+		if aggTrades[i].AggTime != aggTrades[j].AggTime {
+			return aggTrades[i].AggTime < aggTrades[j].AggTime
+		}
+		return aggTrades[i].AggTradeId < aggTrades[j].AggTradeId
+	})
+
+	return aggTrades
+}
+
+// TradeSeries -- aggregated trades indexed by time: sorted by AggTime, so sub-ranges are found by binary search.
+// Useful when a large block of trades is fetched once and sub-ranges are queried repeatedly.
+// AggTime of the trades is expected in milliseconds, for trades fetched with TimeUnitMicrosecond see WithTimeUnit.
+type TradeSeries struct {
+	trades   AggTradesList
+	timeUnit TimeUnit
+}
+
+// NewTradeSeries creates series from aggregated trades (the list is copied and sorted by AggTime, then by AggTradeId).
+func NewTradeSeries(aggTrades AggTradesList) TradeSeries {
+	sorted := make(AggTradesList, len(aggTrades))
+	copy(sorted, aggTrades)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].AggTime != sorted[j].AggTime {
+			return sorted[i].AggTime < sorted[j].AggTime
+		}
+		return sorted[i].AggTradeId < sorted[j].AggTradeId
+	})
+
+	return TradeSeries{trades: sorted}
+}
+
+// Series returns trades of the list as time-indexed TradeSeries.
+func (atl AggTradesList) Series() TradeSeries {
+	return NewTradeSeries(atl)
+}
+
+// WithTimeUnit returns the same series, which treats AggTime of its trades as given unit (see SetTimeUnit),
+// so InRange can be used with bounds in milliseconds for trades fetched with TimeUnitMicrosecond.
+func (ts TradeSeries) WithTimeUnit(unit TimeUnit) TradeSeries {
+	ts.timeUnit = unit
+	return ts
+}
+
+// InRange returns trades with AggTime within [fromMS, toMS] (both inclusive, in milliseconds regardless of the series'
+// time unit). Returned slice shares memory with the series, so it must not be modified.
+func (ts TradeSeries) InRange(fromMS int64, toMS int64) []AggTrade {
+	if fromMS > toMS {
+		return nil
+	}
+
+	from := sort.Search(len(ts.trades), func(i int) bool {
+		return ts.aggTimeMS(i) >= fromMS
+	})
+
+	to := sort.Search(len(ts.trades), func(i int) bool {
+		return ts.aggTimeMS(i) > toMS
+	})
+
+	return ts.trades[from:to]
+}
+
+// aggTimeMS returns AggTime of i-th trade in milliseconds.
+func (ts TradeSeries) aggTimeMS(i int) int64 {
+	if ts.timeUnit == TimeUnitMicrosecond {
+		return ts.trades[i].AggTime / 1000
+	}
+
+	return ts.trades[i].AggTime
+}
+
+// Len returns number of trades in the series.
+func (ts TradeSeries) Len() int {
+	return len(ts.trades)
+}
+
+// Trades returns all trades of the series, sorted by time. Returned slice must not be modified.
+func (ts TradeSeries) Trades() AggTradesList {
+	return ts.trades
+}
+
+// ForEachAggregatedTrade - calls fn for every aggregated trade with AggTime within [fromMS, toMS] (both inclusive),
+// in ascending order. Trades are paginated internally and never more than one page (1000 trades) is held in memory,
+// so millions of trades can be stream-processed to disk or database.
+// When weight controller returns a Warning, it sleeps recommended time and continues. Sleeping can be interrupted
+// by cancelling ctx, in this case ctx.Err() is returned. If fn returns an error, iteration stops and the error is returned.
+func (bc *BinanceClient) ForEachAggregatedTrade(ctx context.Context, symbol string, fromMS int64, toMS int64, fn func(AggTrade) error) error {
+	if fromMS > toMS {
+		return errors.New("fromMS should not be greater than toMS")
+	}
+
+	windowStartMS := fromMS
+	fromId := int64(-1) // Until the first trade is found, trades are searched by time windows, after that - paginated by id
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var page AggTradesList
+		var warning Warning
+		var err error
+
+		if fromId < 0 {
+			if windowStartMS > toMS {
+				return nil
+			}
+
+			windowEndMS := windowStartMS + aggTradesMaxTimeWindowMS - 1
+			if windowEndMS > toMS {
+				windowEndMS = toMS
+			}
+
+			page, warning, err = bc.WithContext(ctx).GetAggregatedTrades(symbol, -1, windowStartMS, windowEndMS, aggTradesMaxLimit)
+			warning, err = splitWarning(warning, err)
+
+			if err == nil && warning == nil && len(page) == 0 {
+				windowStartMS = windowEndMS + 1 // Empty window, try the next one
+				continue
+			}
+		} else {
+			page, warning, err = bc.WithContext(ctx).GetAggregatedTrades(symbol, fromId, -1, -1, aggTradesMaxLimit)
+			warning, err = splitWarning(warning, err)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if warning != nil {
+			if err := sleepWithContext(ctx, warning.GetRetryAfterTimeMS()); err != nil {
+				return err
+			}
+			continue // Repeat the same page after sleep
+		}
+
+		for _, aggTrade := range page {
+			aggTimeMS := bc.timestampToMS(aggTrade.AggTime) // AggTime is in microseconds with TimeUnitMicrosecond
+
+			if aggTimeMS > toMS {
+				return nil
+			}
+
+			if aggTimeMS < fromMS {
+				continue
+			}
+
+			if err := fn(aggTrade); err != nil {
+				return err
+			}
+		}
+
+		if fromId >= 0 && len(page) < aggTradesMaxLimit {
+			return nil // There are no more trades at the moment
+		}
+
+		if len(page) > 0 {
+			fromId = page[len(page)-1].AggTradeId + 1
+		}
+	}
+}
+
+// VolumeImbalance - splits volume of aggregated trades by aggressor (taker) side.
+// ATTENTION to semantics of AggIsBuyerMaker: it's true when the BUYER was the maker (his order was resting in the book),
+// so the aggressor was the SELLER - this is a sell (taker sell hits the bid). When it's false, the buyer was the taker -
+// this is a buy. Returned buyVol is volume of taker buys, sellVol - volume of taker sells (both in base asset).
+func (atl AggTradesList) VolumeImbalance() (float64, float64) {
+	buyVol, sellVol := 0.0, 0.0
+
+	for _, aggTrade := range atl {
+		if aggTrade.AggIsBuyerMaker {
+			sellVol += aggTrade.AggQty
+		} else {
+			buyVol += aggTrade.AggQty
+		}
+	}
+
+	return buyVol, sellVol
+}
+
+// SignedVolume - net taker flow: volume of taker buys minus volume of taker sells (see VolumeImbalance for semantics).
+// Positive value means buy pressure, negative - sell pressure.
+func (atl AggTradesList) SignedVolume() float64 {
+	buyVol, sellVol := atl.VolumeImbalance()
+	return buyVol - sellVol
+}
@@ -0,0 +1,212 @@
+package bncclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAggTradesDeduplicatorCraftedOverlap(t *testing.T) {
+	var dedup AggTradesDeduplicator
+
+	if dedup.LastEmittedId() != -1 {
+		t.Fatalf("expected -1 before anything is emitted, got %d", dedup.LastEmittedId())
+	}
+
+	// Trades 3, 4 and 5 share the same timestamp, and the second page (requested from that timestamp) repeats 3 and 4:
+	firstPage := AggTradesList{
+		{AggTradeId: 1, AggTime: 1000},
+		{AggTradeId: 2, AggTime: 1001},
+		{AggTradeId: 3, AggTime: 1002},
+		{AggTradeId: 4, AggTime: 1002},
+	}
+	secondPage := AggTradesList{
+		{AggTradeId: 3, AggTime: 1002},
+		{AggTradeId: 4, AggTime: 1002},
+		{AggTradeId: 5, AggTime: 1002},
+		{AggTradeId: 6, AggTime: 1003},
+	}
+
+	emitted := append(dedup.Filter(firstPage), dedup.Filter(secondPage)...)
+
+	if len(emitted) != 6 {
+		t.Fatalf("expected 6 unique trades, got %d: %+v", len(emitted), emitted)
+	}
+
+	for i, aggTrade := range emitted {
+		if aggTrade.AggTradeId != int64(i+1) {
+			t.Fatalf("trade %d: expected id %d, got %d", i, i+1, aggTrade.AggTradeId)
+		}
+	}
+
+	// Trade 5 has the same timestamp as already emitted ones, but it's new, so it must not be dropped:
+	if emitted[4].AggTime != emitted[3].AggTime {
+		t.Fatalf("crafted overlap is broken: %+v", emitted)
+	}
+
+	if dedup.LastEmittedId() != 6 {
+		t.Fatalf("expected last emitted id 6, got %d", dedup.LastEmittedId())
+	}
+
+	if repeated := dedup.Filter(secondPage); len(repeated) != 0 {
+		t.Fatalf("expected repeated page to be filtered out entirely, got %+v", repeated)
+	}
+}
+
+func TestSortAggTradesSameTimestamp(t *testing.T) {
+	tradesById := map[int64]AggTrade{
+		7: {AggTradeId: 7, AggTime: 2000},
+		5: {AggTradeId: 5, AggTime: 1000},
+		6: {AggTradeId: 6, AggTime: 1000},
+	}
+
+	sorted := sortAggTrades(tradesById)
+
+	for i, expectedId := range []int64{5, 6, 7} {
+		if sorted[i].AggTradeId != expectedId {
+			t.Fatalf("position %d: expected id %d, got %d", i, expectedId, sorted[i].AggTradeId)
+		}
+	}
+}
+
+// aggTradesResponse -- JSON array of count aggregated trades with ids starting from firstId.
+func aggTradesResponse(firstId int64, count int) string {
+	var body strings.Builder
+
+	body.WriteString("[")
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			body.WriteString(",")
+		}
+		id := firstId + int64(i)
+		fmt.Fprintf(&body, `{"a":%d,"p":"1.0","q":"1.0","f":%d,"l":%d,"T":%d,"m":true}`, id, id, id, 1499865549590+id)
+	}
+	body.WriteString("]")
+
+	return body.String()
+}
+
+func TestGetAggregatedTradesDefaultLimit(t *testing.T) {
+	var rawQuery string
+
+	bc := NewTestnetClient("test-api-key", "test-secret-key")
+	bc.SetHTTPClient(doerFunc(func(request *http.Request) (*http.Response, error) {
+		rawQuery = request.URL.RawQuery
+		// Without filters Binance returns DefaultAggTradesLimit most recent trades, the latest one is the last:
+		return jsonResponse(request, aggTradesResponse(10000, DefaultAggTradesLimit)), nil
+	}))
+
+	aggTrades, warning, err := bc.GetAggregatedTrades("BTCUSDT", -1, -1, -1, -1)
+	if err != nil || warning != nil {
+		t.Fatalf("unexpected warning %v or error %v", warning, err)
+	}
+
+	if rawQuery != "symbol=BTCUSDT" {
+		t.Errorf("expected only symbol in query, got %s", rawQuery)
+	}
+
+	if len(aggTrades) != DefaultAggTradesLimit || aggTrades[len(aggTrades)-1].AggTradeId != 10000+DefaultAggTradesLimit-1 {
+		t.Fatalf("expected %d most recent trades in ascending order", DefaultAggTradesLimit)
+	}
+
+	// Full page of default size means there can be more trades, so the next cursor is returned:
+	_, nextFromId, _, _ := bc.GetAggregatedTradesPage("BTCUSDT", 10000, -1)
+	if nextFromId != 10000+DefaultAggTradesLimit {
+		t.Errorf("expected next fromId %d, got %d", 10000+DefaultAggTradesLimit, nextFromId)
+	}
+}
+
+func TestAggTradeMissingBestMatch(t *testing.T) {
+	var aggTrades AggTradesList
+	body := `[{"a":1,"p":"1.0","q":"1.0","f":1,"l":1,"T":1499865549590,"m":true},` +
+		`{"a":2,"p":"1.0","q":"1.0","f":2,"l":2,"T":1499865549590,"m":true,"M":false},` +
+		`{"a":3,"p":"1.0","q":"1.0","f":3,"l":3,"T":1499865549590,"m":true,"M":true}]`
+
+	if err := NewBinanceClient("").tryParseArrayResponse([]byte(body), &aggTrades); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		bestMatch bool
+		present   bool
+	}{{false, false}, {false, true}, {true, true}}
+
+	for i, expectation := range expected {
+		bestMatch, present := aggTrades[i].BestMatch()
+		if bestMatch != expectation.bestMatch || present != expectation.present {
+			t.Errorf("trade %d: expected (%v, %v), got (%v, %v)", i, expectation.bestMatch, expectation.present, bestMatch, present)
+		}
+	}
+
+	// Missing field is not serialized back as explicit false:
+	encoded, err := json.Marshal(aggTrades[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(encoded), `"M"`) {
+		t.Errorf("expected missing M to stay missing, got %s", encoded)
+	}
+}
+
+func TestGetAggregatedTradesParamCombinations(t *testing.T) {
+	const hourMS = 60 * 60 * 1000
+
+	testCases := []struct {
+		name          string
+		fromId        int64
+		startTimeMS   int64
+		endTimeMS     int64
+		limit         int
+		expectedQuery string
+		expectedErr   error
+	}{
+		{"nothing", -1, -1, -1, -1, "symbol=BTCUSDT", nil},
+		{"limit", -1, -1, -1, 1000, "limit=1000&symbol=BTCUSDT", nil},
+		{"fromId", 42, -1, -1, -1, "fromId=42&symbol=BTCUSDT", nil},
+		{"fromId and limit", 42, -1, -1, 10, "fromId=42&limit=10&symbol=BTCUSDT", nil},
+		{"fromId zero", 0, -1, -1, -1, "fromId=0&symbol=BTCUSDT", nil},
+		{"startTime", -1, 1000, -1, -1, "startTime=1000&symbol=BTCUSDT", nil},
+		{"endTime", -1, -1, 2000, -1, "endTime=2000&symbol=BTCUSDT", nil},
+		{"time window and limit", -1, 1000, 1000 + hourMS, 5, "endTime=3601000&limit=5&startTime=1000&symbol=BTCUSDT", nil},
+		{"limit too large", -1, -1, -1, 1001, "", ErrInvalidLimit},
+		{"limit zero", -1, -1, -1, 0, "", ErrInvalidLimit},
+		{"fromId with startTime", 42, 1000, -1, -1, "", ErrConflictingParams},
+		{"fromId with endTime", 42, -1, 2000, -1, "", ErrConflictingParams},
+		{"window too large", -1, 1000, 1001 + hourMS, -1, "", ErrTimeWindowTooLarge},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			rawQuery := ""
+
+			bc := NewTestnetClient("test-api-key", "test-secret-key")
+			bc.SetHTTPClient(doerFunc(func(request *http.Request) (*http.Response, error) {
+				rawQuery = request.URL.RawQuery
+				return jsonResponse(request, `[]`), nil
+			}))
+
+			_, _, err := bc.GetAggregatedTrades("BTCUSDT", testCase.fromId, testCase.startTimeMS, testCase.endTimeMS, testCase.limit)
+
+			if testCase.expectedErr != nil {
+				if !errors.Is(err, testCase.expectedErr) {
+					t.Fatalf("expected %v, got %v", testCase.expectedErr, err)
+				}
+				if rawQuery != "" {
+					t.Fatalf("expected no request, got %s", rawQuery)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if rawQuery != testCase.expectedQuery {
+				t.Fatalf("expected query %s, got %s", testCase.expectedQuery, rawQuery)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package bncclient
+
+import (
+	"net/http"
+)
+
+// KeyPermissions -- restrictions of API key.
+// Details: https://binance-docs.github.io/apidocs/spot/en/#get-api-key-permission-user_data
+// If the client has no secret key, only validity of the key can be checked, then PermissionsKnown is false
+// and all other fields are zero.
+type KeyPermissions struct {
+	PermissionsKnown               bool  `json:"-"`
+	IpRestrict                     bool  `json:"ipRestrict"`
+	CreateTime                     int64 `json:"createTime"`
+	EnableReading                  bool  `json:"enableReading"`
+	EnableSpotAndMarginTrading     bool  `json:"enableSpotAndMarginTrading"`
+	EnableWithdrawals              bool  `json:"enableWithdrawals"`
+	EnableInternalTransfer         bool  `json:"enableInternalTransfer"`
+	PermitsUniversalTransfer       bool  `json:"permitsUniversalTransfer"`
+	EnableMargin                   bool  `json:"enableMargin"`
+	EnableFutures                  bool  `json:"enableFutures"`
+	EnableVanillaOptions           bool  `json:"enableVanillaOptions"`
+	TradingAuthorityExpirationTime int64 `json:"tradingAuthorityExpirationTime"`
+}
+
+// VerifyAPIKey - checks that API key (and secret key, if set) is accepted by Binance, and returns key permissions,
+// so misconfiguration is detected up-front, instead of cryptic -2015 "Invalid API-key, IP, or permissions for action" later.
+// For signed client permissions are requested from SAPI endpoint. Client without secret key can check only validity
+// of the key: the most recent BTCUSDT trade is requested from historical trades endpoint, which requires API key
+// (read-only request of weight 5, without side effects).
+// Invalid key is reported as error, which matches errors.Is(err, ErrorCode(-2014)) or errors.Is(err, ErrorCode(-2015)).
+func (bc *BinanceClient) VerifyAPIKey() (KeyPermissions, Warning, error) {
+	if bc.secretKey == "" {
+		return bc.verifyAPIKeyWithoutSecret()
+	}
+
+	var keyPermissions KeyPermissions
+
+	warning, err := bc.newSignedRequest(http.MethodGet).Do("/sapi/v1/account/apiRestrictions", 1, &keyPermissions)
+
+	if err != nil || warning != nil {
+		return KeyPermissions{}, warning, err
+	}
+
+	keyPermissions.PermissionsKnown = true
+
+	return keyPermissions, nil, nil
+}
+
+// apiKeyCheckSymbol -- symbol which is used to check API key without secret key (it exists on both live and test network).
+const apiKeyCheckSymbol = "BTCUSDT"
+
+func (bc *BinanceClient) verifyAPIKeyWithoutSecret() (KeyPermissions, Warning, error) {
+	// With key pool the request could be made with another key of the pool, so the client's own key is used explicitly:
+	ownKeyClient := *bc
+	ownKeyClient.keyPool = nil
+
+	_, warning, err := ownKeyClient.GetHistoricalTrades(apiKeyCheckSymbol, 1, -1)
+
+	if err != nil || warning != nil {
+		return KeyPermissions{}, warning, err
+	}
+
+	return KeyPermissions{}, nil, nil
+}
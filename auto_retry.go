@@ -0,0 +1,52 @@
+package bncclient
+
+import (
+	"context"
+	"net/http"
+)
+
+const autoRetryMaxAttempts = 5 // How many times request is made (including the first attempt) when auto-retry is enabled
+
+// SetAutoRetry - when enabled, requests which got a Warning (rate limit, WAF limit, temporary server or network problem)
+// are retried automatically after recommended time, up to 5 attempts in total, and the Warning is returned only if
+// all attempts failed. Ban (418) is never retried. Requests which change state (POST, PUT, DELETE) are retried only
+// after rate limit Warnings, when it's known the request was not processed. Sleep between attempts is interrupted
+// when context of the client is cancelled (see WithContext), then ctx.Err() is returned.
+func (bc *BinanceClient) SetAutoRetry(enabled bool) {
+	bc.autoRetry = enabled
+}
+
+// WithContext - returns copy of the client, which makes requests (and sleeps between auto-retry attempts) within ctx:
+// when ctx is cancelled, in-flight request is aborted, request parked by auto-retry is dropped without consuming weight,
+// and ctx.Err() is returned. The copy is shallow: it shares with the original client weight controllers, caches, streams,
+// time offset and the HTTP client with its transport, as well as default headers. So SetDefaultHeader, SetUserAgent,
+// SetProxy, SetTimeout, SetTransportTimeouts, SetTransportOptions and setters of shared state (SetExchangeInfoTTL,
+// SetDryRun, SetCloudFrontBackoff, SetMaintenanceDetection, SetKeySelectionStrategy, SetAutoTimeSync) called on the copy
+// affect the original too. Only plain settings, like SetWarningsAsErrors or SetAutoRetry, are independent.
+func (bc *BinanceClient) WithContext(ctx context.Context) *BinanceClient {
+	clientCopy := *bc
+	clientCopy.ctx = ctx
+
+	return &clientCopy
+}
+
+// context returns context of requests made by the client.
+func (bc *BinanceClient) context() context.Context {
+	if bc.ctx == nil {
+		return context.Background()
+	}
+
+	return bc.ctx
+}
+
+// isRetryableWarning checks if request can be safely retried after the Warning.
+func isRetryableWarning(method string, warning Warning) bool {
+	switch warning.Kind() {
+	case WarnBanned:
+		return false
+	case WarnRateLimit, WarnCloudFront:
+		return true
+	default: // Server or network failure: request could be processed, repeating it may duplicate the action
+		return method == http.MethodGet
+	}
+}
@@ -0,0 +1,102 @@
+package bncclient
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrRateLimited is a cause of warnings returned when request limit is reached (by local weight controller, or Binance
+// answered with 429 or 403 WAF limit). Use errors.Is(warning, ErrRateLimited).
+var ErrRateLimited = errors.New("rate limited")
+
+// ErrBanned is a cause of warning returned when Binance has banned the IP (status 418).
+var ErrBanned = errors.New("banned by Binance")
+
+// ErrNetwork is a cause of warning returned on temporary network failure. Underlying network error can be
+// extracted with errors.As too (for example, to net.Error).
+var ErrNetwork = errors.New("network failure")
+
+// ErrServerError is a cause of warning returned when Binance has internal problems (statuses 500, 502, 503, 504).
+var ErrServerError = errors.New("Binance server error")
+
+// ErrConflictingParams is returned when mutually exclusive parameters are specified together (Binance would reject them with -1128).
+var ErrConflictingParams = errors.New("conflicting parameters: fromId can't be combined with startTime/endTime")
+
+// ErrTimeWindowTooLarge is returned when time window between startTime and endTime exceeds maximum allowed by Binance.
+var ErrTimeWindowTooLarge = errors.New("time window between startTime and endTime is too large")
+
+// ErrNoMoreTrades is returned by iterators when all requested trades were already returned (like io.EOF).
+var ErrNoMoreTrades = errors.New("no more trades")
+
+// ErrResponseTooLarge is returned when response body exceeds maximum allowed size (16MB).
+var ErrResponseTooLarge = errors.New("response body is too large")
+
+// ErrInvalidLimit is returned when limit is out of range allowed by Binance for the endpoint.
+var ErrInvalidLimit = errors.New("limit is out of allowed range")
+
+// ErrRequestWeightTooHigh is returned when weight of a single request exceeds cap set by SetMaxSingleRequestWeight.
+var ErrRequestWeightTooHigh = errors.New("request weight exceeds configured maximum")
+
+// ErrFilterFailure is returned when order parameters violate symbol filters (Binance would reject them with -1013).
+var ErrFilterFailure = errors.New("filter failure")
+
+// BinanceError -- native Binance error (with Binance error code and message), usable as errors.As target:
+//
+//	var bncErr BinanceError
+//	if errors.As(err, &bncErr) { code := bncErr.GetCode() }
+type BinanceError interface {
+	error
+	GetCode() int
+	GetMsg() string
+}
+
+// ErrorCode -- native Binance error code. Native Binance errors unwrap to their code, so they can be matched
+// with errors.Is(err, ErrorCode(-1121)).
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/errors.md
+type ErrorCode int
+
+func (c ErrorCode) Error() string {
+	return fmt.Sprintf("Binance error code %d", int(c))
+}
+
+// sentinelCause -- error which matches sentinel with errors.Is and unwraps to the original error.
+type sentinelCause struct {
+	sentinel error
+	err      error
+}
+
+func (e sentinelCause) Error() string {
+	return fmt.Sprintf("%s: %s", e.sentinel.Error(), e.err.Error())
+}
+
+func (e sentinelCause) Is(target error) bool {
+	return target == e.sentinel
+}
+
+func (e sentinelCause) Unwrap() error {
+	return e.err
+}
+
+// ErrMissingSecretKey is returned when SIGNED endpoint is called, but the client has no secret key.
+var ErrMissingSecretKey = errors.New("secret key is required for signed endpoints")
+
+// ErrMissingAPIKey is returned when endpoint which requires API key is called, but the client has no API key (see NewPublicClient).
+var ErrMissingAPIKey = errors.New("API key is required for this endpoint")
+
+// ErrInvalidOrder is returned when order request is rejected by client-side validation (before sending to Binance).
+var ErrInvalidOrder = errors.New("invalid order")
+
+// ErrUnexpectedResponse is returned when successful Binance response has unexpected shape (for example, object or null instead of array).
+var ErrUnexpectedResponse = errors.New("unexpected Binance response shape")
+
+// ErrClientClosed is returned by any request made after BinanceClient.Close().
+var ErrClientClosed = errors.New("client is closed")
+
+// ErrSystemMaintenance is returned by market data requests when Binance is in maintenance (see SetMaintenanceDetection).
+var ErrSystemMaintenance = errors.New("Binance system maintenance")
+
+// ErrOrderBookDiverged is reported when locally maintained order book doesn't match Binance snapshot.
+var ErrOrderBookDiverged = errors.New("local order book diverged from Binance snapshot")
+
+// ErrOrderBookNotSynced is returned when local order book is not synchronized with Binance (yet or anymore).
+var ErrOrderBookNotSynced = errors.New("local order book is not synchronized")
@@ -3,15 +3,50 @@ package bncclient
 type Warning interface {
 	Error() string
 	GetRetryAfterTimeMS() int64
+	Kind() WarningKind
 }
 
-func newWaring(retryAfter int64, message string) Warning {
-	return warningSt{retryAfter: retryAfter, message: message}
+// WarningKind -- reason of Warning, so callers can apply different strategies (alert on ban, silently retry on network etc).
+type WarningKind int
+
+const (
+	WarnUnknown     WarningKind = iota
+	WarnRateLimit               // Local weight controller or Binance (429) asks to slow down
+	WarnBanned                  // IP is banned by Binance (418)
+	WarnCloudFront              // WAF limit violated (403)
+	WarnNetwork                 // Temporary network failure
+	WarnServerError             // Binance has internal problems (500, 502, 503, 504)
+)
+
+// String implements fmt.Stringer.
+func (k WarningKind) String() string {
+	switch k {
+	case WarnRateLimit:
+		return "RateLimit"
+	case WarnBanned:
+		return "Banned"
+	case WarnCloudFront:
+		return "CloudFront"
+	case WarnNetwork:
+		return "Network"
+	case WarnServerError:
+		return "ServerError"
+	default:
+		return "Unknown"
+	}
+}
+
+// newWarningWithCause creates warning which can be matched with errors.Is/errors.As against its cause
+// (like ErrRateLimited or underlying network error).
+func newWarningWithCause(kind WarningKind, retryAfter int64, message string, cause error) Warning {
+	return warningSt{kind: kind, retryAfter: retryAfter, message: message, cause: cause}
 }
 
 type warningSt struct {
+	kind       WarningKind
 	retryAfter int64
 	message    string
+	cause      error
 }
 
 func (w warningSt) Error() string { // warning structure implementing "error" interface
@@ -21,3 +56,12 @@ func (w warningSt) Error() string { // warning structure implementing "error" in
 func (w warningSt) GetRetryAfterTimeMS() int64 {
 	return w.retryAfter
 }
+
+func (w warningSt) Kind() WarningKind {
+	return w.kind
+}
+
+// Unwrap returns cause of the warning, so errors.Is(warning, ErrRateLimited) works.
+func (w warningSt) Unwrap() error {
+	return w.cause
+}
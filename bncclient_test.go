@@ -0,0 +1,173 @@
+package bncclient_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anxp/bncclient"
+	"github.com/anxp/bncclient/testutil"
+)
+
+func TestGetOrderBook(t *testing.T) {
+	client, doer := testutil.NewClient(map[string]string{
+		"/api/v3/depth": `{"lastUpdateId":1027024,"bids":[["4.00000000","431.00000000"],["3.90000000","12.50000000"]],"asks":[["4.00000200","12.00000000"]]}`,
+	})
+
+	orderBook, warning, err := client.GetOrderBook("BNBBTC", 5)
+	if err != nil || warning != nil {
+		t.Fatalf("unexpected warning %v or error %v", warning, err)
+	}
+
+	if orderBook.LastUpdateId != 1027024 {
+		t.Errorf("expected lastUpdateId 1027024, got %d", orderBook.LastUpdateId)
+	}
+
+	expectedBids := [][2]float64{{4.0, 431.0}, {3.9, 12.5}}
+	if len(orderBook.Bids) != len(expectedBids) {
+		t.Fatalf("expected %d bids, got %d", len(expectedBids), len(orderBook.Bids))
+	}
+	for i, bid := range expectedBids {
+		if orderBook.Bids[i].Price != bid[0] || orderBook.Bids[i].Qty != bid[1] {
+			t.Errorf("bid %d: expected %v, got %+v", i, bid, orderBook.Bids[i])
+		}
+	}
+
+	if len(orderBook.Asks) != 1 || orderBook.Asks[0].Price != 4.000002 || orderBook.Asks[0].Qty != 12.0 {
+		t.Errorf("unexpected asks: %+v", orderBook.Asks)
+	}
+
+	requests := doer.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+
+	query := requests[0].URL.Query()
+	if query.Get("symbol") != "BNBBTC" || query.Get("limit") != "5" {
+		t.Errorf("unexpected query: %s", requests[0].URL.RawQuery)
+	}
+
+	if requests[0].Header.Get("X-MBX-APIKEY") != "test-api-key" {
+		t.Errorf("expected API key header to be sent")
+	}
+}
+
+func TestGetOrderBookInvalidLimit(t *testing.T) {
+	client, doer := testutil.NewClient(nil)
+
+	for _, limit := range []int{0, -2, 5001} {
+		if _, _, err := client.GetOrderBook("BNBBTC", limit); !errors.Is(err, bncclient.ErrInvalidLimit) {
+			t.Errorf("limit %d: expected ErrInvalidLimit, got %v", limit, err)
+		}
+
+		if _, _, err := client.GetOrderBooks([]string{"BNBBTC"}, limit); !errors.Is(err, bncclient.ErrInvalidLimit) {
+			t.Errorf("limit %d: expected ErrInvalidLimit from GetOrderBooks, got %v", limit, err)
+		}
+	}
+
+	if len(doer.Requests()) != 0 {
+		t.Errorf("expected no requests with invalid limit")
+	}
+}
+
+func TestGetKlines(t *testing.T) {
+	client, doer := testutil.NewClient(map[string]string{
+		"/api/v3/klines": `[[1499040000000,"0.01634790","0.80000000","0.01575800","0.01577100","148976.11427815",1499644799999,"2434.19055334",308,"1756.87402397","28.46694368","0"]]`,
+	})
+
+	klines, warning, err := client.GetKlines("BNBBTC", "1m", 1499040000000, -1, 10)
+	if err != nil || warning != nil {
+		t.Fatalf("unexpected warning %v or error %v", warning, err)
+	}
+
+	expected := bncclient.Kline{
+		OpenTime:                 1499040000000,
+		Open:                     0.0163479,
+		High:                     0.8,
+		Low:                      0.015758,
+		Close:                    0.015771,
+		Volume:                   148976.11427815,
+		CloseTime:                1499644799999,
+		QuoteAssetVolume:         2434.19055334,
+		NumberOfTrades:           308,
+		TakerBuyBaseAssetVolume:  1756.87402397,
+		TakerBuyQuoteAssetVolume: 28.46694368,
+	}
+
+	if len(klines) != 1 || klines[0] != expected {
+		t.Fatalf("expected %+v, got %+v", expected, klines)
+	}
+
+	query := doer.Requests()[0].URL.Query()
+	if query.Get("interval") != "1m" || query.Get("startTime") != "1499040000000" || query.Get("limit") != "10" {
+		t.Errorf("unexpected query: %s", doer.Requests()[0].URL.RawQuery)
+	}
+
+	if _, present := query["endTime"]; present {
+		t.Errorf("endTime -1 must not be sent")
+	}
+}
+
+func TestBinanceErrorResponse(t *testing.T) {
+	client, doer := testutil.NewClient(nil)
+	doer.SetResponse("/api/v3/depth", testutil.CannedResponse{StatusCode: 400, Body: `{"code":-1121,"msg":"Invalid symbol."}`})
+
+	_, _, err := client.GetOrderBook("NOPE", 5)
+
+	var binanceErr bncclient.BinanceError
+	if !errors.As(err, &binanceErr) || binanceErr.GetCode() != -1121 {
+		t.Fatalf("expected Binance error -1121, got %v", err)
+	}
+
+	if !errors.Is(err, bncclient.ErrorCode(-1121)) {
+		t.Fatalf("expected error to match its code, got %v", err)
+	}
+}
+
+func TestAutoUppercaseSymbols(t *testing.T) {
+	client, doer := testutil.NewClient(map[string]string{
+		"/api/v3/depth": `{"lastUpdateId":1,"bids":[],"asks":[]}`,
+	})
+
+	if _, _, err := client.GetOrderBook("ethusdt", 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client.SetAutoUppercaseSymbols(false)
+
+	if _, _, err := client.GetOrderBook("ethusdt", 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	requests := doer.Requests()
+	if symbol := requests[0].URL.Query().Get("symbol"); symbol != "ETHUSDT" {
+		t.Errorf("expected uppercase symbol by default, got %s", symbol)
+	}
+
+	if symbol := requests[1].URL.Query().Get("symbol"); symbol != "ethusdt" {
+		t.Errorf("expected symbol as is with auto-uppercasing disabled, got %s", symbol)
+	}
+}
+
+func TestGetAvgPriceCloseTime(t *testing.T) {
+	bodies := map[string]bncclient.AvgPrice{
+		`{"mins":5,"price":"9.35751834","closeTime":1694061154503}`: {Mins: 5, Price: 9.35751834, CloseTime: 1694061154503},
+		`{"mins":5,"price":"9.35751834"}`:                           {Mins: 5, Price: 9.35751834}, // Older response
+	}
+
+	for body, expected := range bodies {
+		client, doer := testutil.NewClient(map[string]string{"/api/v3/avgPrice": body})
+
+		avgPrice, warning, err := client.GetAvgPrice("BNBUSDT")
+		if err != nil || warning != nil {
+			t.Fatalf("unexpected warning %v or error %v", warning, err)
+		}
+
+		if avgPrice != expected {
+			t.Errorf("%s: expected %+v, got %+v", body, expected, avgPrice)
+		}
+
+		if symbol := doer.Requests()[0].URL.Query().Get("symbol"); symbol != "BNBUSDT" {
+			t.Errorf("expected symbol BNBUSDT, got %s", symbol)
+		}
+	}
+}
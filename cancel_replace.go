@@ -0,0 +1,221 @@
+package bncclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// CancelReplaceMode -- what to do if cancellation of existing order fails.
+type CancelReplaceMode string
+
+const (
+	CancelReplaceStopOnFailure CancelReplaceMode = "STOP_ON_FAILURE" // New order is not placed if cancel fails
+	CancelReplaceAllowFailure  CancelReplaceMode = "ALLOW_FAILURE"   // New order is placed even if cancel fails
+)
+
+// Results of cancel and new order parts of cancel-replace operation.
+const (
+	CancelReplaceResultSuccess      = "SUCCESS"
+	CancelReplaceResultFailure      = "FAILURE"
+	CancelReplaceResultNotAttempted = "NOT_ATTEMPTED"
+)
+
+// CancelReplaceRequest -- order to cancel (CancelOrderId or CancelOrigClientOrderId) and parameters of the new order
+// (the same as in OrderRequest, see there which fields are required for every order type).
+// NewClientOrderId is optional (empty means CancelReplaceOrder generates random UUID).
+type CancelReplaceRequest struct {
+	Symbol                  string
+	Mode                    CancelReplaceMode
+	CancelOrderId           int64 // 0 means not specified
+	CancelOrigClientOrderId string
+	Side                    OrderSide
+	Type                    OrderType
+	TimeInForce             TimeInForce
+	Quantity                float64
+	QuoteOrderQty           float64
+	Price                   float64
+	StopPrice               float64
+	NewClientOrderId        string
+}
+
+// CancelReplaceResponse -- outcome of both parts of cancel-replace operation. Every part is either successful
+// (then its response is set), or failed (then its error is set), or not attempted (both are nil).
+type CancelReplaceResponse struct {
+	CancelResult     string // SUCCESS, FAILURE or NOT_ATTEMPTED
+	NewOrderResult   string // SUCCESS, FAILURE or NOT_ATTEMPTED
+	CancelResponse   *OrderResponse
+	CancelError      error // Native Binance error of cancel part (BinanceError)
+	NewOrderResponse *OrderResponse
+	NewOrderError    error  // Native Binance error of new order part (BinanceError)
+	NewClientOrderId string // Client order id of the new order (generated, if not set in request), set even on failure
+}
+
+// CancelReplaceOrder - atomically cancels existing order and places a new one (requote without cancel/place race).
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#cancel-an-existing-order-and-send-a-new-order-trade
+// When the operation fails entirely (-2021) or partially (-2022, for example cancel succeeded but the new order was rejected),
+// the error is returned TOGETHER with filled response, so the caller can check which part failed and why.
+// If NewClientOrderId is not set, it is generated (like in PlaceOrder), and returned in NewClientOrderId of the response
+// even when Warning or error is returned, so retry can't place a duplicate of the new order.
+func (bc *BinanceClient) CancelReplaceOrder(req CancelReplaceRequest) (CancelReplaceResponse, Warning, error) {
+	if err := req.validate(); err != nil {
+		return CancelReplaceResponse{}, nil, err
+	}
+
+	if req.NewClientOrderId == "" {
+		clientOrderId, err := newClientOrderId()
+		if err != nil {
+			return CancelReplaceResponse{}, nil, err
+		}
+		req.NewClientOrderId = clientOrderId
+	}
+
+	failedResponse := CancelReplaceResponse{NewClientOrderId: req.NewClientOrderId}
+
+	if warning := bc.acquireOrderRate(1); warning != nil {
+		if bc.warningsAsErrors {
+			return failedResponse, nil, warning
+		}
+		return failedResponse, warning, nil
+	}
+
+	request := bc.newSignedRequest(http.MethodPost).
+		AddString("symbol", req.Symbol).
+		AddString("cancelReplaceMode", string(req.Mode)).
+		AddString("side", req.Side.String()).
+		AddString("type", req.Type.String()).
+		AddString("newOrderRespType", "RESULT").
+		AddString("cancelOrigClientOrderId", req.CancelOrigClientOrderId).
+		AddString("timeInForce", req.TimeInForce.String()).
+		AddFloat("quantity", req.Quantity).
+		AddFloat("quoteOrderQty", req.QuoteOrderQty).
+		AddFloat("price", req.Price).
+		AddFloat("stopPrice", req.StopPrice).
+		AddString("newClientOrderId", req.NewClientOrderId)
+
+	if req.CancelOrderId > 0 {
+		request.AddInt64("cancelOrderId", req.CancelOrderId, false)
+	}
+
+	bodyBytes, statusCode, _, warning, err := request.DoRaw("/api/v3/order/cancelReplace", 1)
+
+	// Failed and partially failed operations come with 4xx status, but their body contains results of both parts:
+	if err != nil && statusCode >= 400 && statusCode < 500 {
+		if response, isCancelReplaceFailure := parseCancelReplaceFailure(bodyBytes); isCancelReplaceFailure {
+			response.NewClientOrderId = req.NewClientOrderId
+			return response, nil, err
+		}
+	}
+
+	if err != nil {
+		return failedResponse, nil, err
+	}
+
+	if warning != nil {
+		return failedResponse, warning, nil
+	}
+
+	var responseTmp cancelReplaceWireFormat
+	if err := bc.tryParseResponse(bodyBytes, &responseTmp); err != nil {
+		return failedResponse, nil, err
+	}
+
+	response, err := responseTmp.toResponse()
+	if err != nil {
+		return failedResponse, nil, err
+	}
+
+	response.NewClientOrderId = req.NewClientOrderId
+
+	return response, nil, nil
+}
+
+func (req CancelReplaceRequest) validate() error {
+	if req.Mode != CancelReplaceStopOnFailure && req.Mode != CancelReplaceAllowFailure {
+		return fmt.Errorf("%w: not allowed cancel replace mode: \"%s\"", ErrInvalidOrder, req.Mode)
+	}
+
+	if req.CancelOrderId <= 0 && req.CancelOrigClientOrderId == "" {
+		return fmt.Errorf("%w: cancelOrderId or cancelOrigClientOrderId is required", ErrInvalidOrder)
+	}
+
+	newOrder := OrderRequest{
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		TimeInForce:   req.TimeInForce,
+		Quantity:      req.Quantity,
+		QuoteOrderQty: req.QuoteOrderQty,
+		Price:         req.Price,
+		StopPrice:     req.StopPrice,
+	}
+
+	return newOrder.validate()
+}
+
+// cancelReplaceWireFormat -- cancel-replace result as Binance sends it. Every part response is either order or error object.
+type cancelReplaceWireFormat struct {
+	CancelResult     string          `json:"cancelResult"`
+	NewOrderResult   string          `json:"newOrderResult"`
+	CancelResponse   json.RawMessage `json:"cancelResponse"`
+	NewOrderResponse json.RawMessage `json:"newOrderResponse"`
+}
+
+func (w cancelReplaceWireFormat) toResponse() (CancelReplaceResponse, error) {
+	response := CancelReplaceResponse{CancelResult: w.CancelResult, NewOrderResult: w.NewOrderResult}
+	var err error
+
+	response.CancelResponse, response.CancelError, err = parseCancelReplacePart(w.CancelResponse)
+	if err != nil {
+		return CancelReplaceResponse{}, err
+	}
+
+	response.NewOrderResponse, response.NewOrderError, err = parseCancelReplacePart(w.NewOrderResponse)
+	if err != nil {
+		return CancelReplaceResponse{}, err
+	}
+
+	return response, nil
+}
+
+// parseCancelReplacePart parses response of one part: order (first returned value), Binance error (second one),
+// or nothing, if the part was not attempted.
+func parseCancelReplacePart(rawPart json.RawMessage) (*OrderResponse, error, error) {
+	if len(rawPart) == 0 || string(rawPart) == "null" {
+		return nil, nil, nil
+	}
+
+	if binanceErr, isBinanceError := parseBinanceError(rawPart); isBinanceError {
+		return nil, binanceErr, nil
+	}
+
+	var order OrderResponse
+	if err := json.Unmarshal(rawPart, &order); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse Binance response: %w", err)
+	}
+
+	return &order, nil, nil
+}
+
+// parseCancelReplaceFailure parses body of failed cancel-replace: Binance error with results of both parts in "data".
+// Second returned value is false if the body has another shape.
+func parseCancelReplaceFailure(bodyBytes []byte) (CancelReplaceResponse, bool) {
+	if _, isBinanceError := parseBinanceError(bodyBytes); !isBinanceError {
+		return CancelReplaceResponse{}, false
+	}
+
+	var failureShape struct {
+		Data *cancelReplaceWireFormat `json:"data"`
+	}
+
+	if json.Unmarshal(bodyBytes, &failureShape) != nil || failureShape.Data == nil || failureShape.Data.CancelResult == "" {
+		return CancelReplaceResponse{}, false
+	}
+
+	response, err := failureShape.Data.toResponse()
+	if err != nil {
+		return CancelReplaceResponse{}, false
+	}
+
+	return response, true
+}
@@ -0,0 +1,151 @@
+package bncclient
+
+import (
+	"net/http"
+	"time"
+)
+
+// binanceDateTimeLayout -- layout of date-time strings used by some SAPI endpoints (always in UTC).
+const binanceDateTimeLayout = "2006-01-02 15:04:05"
+
+// Deposit statuses.
+const (
+	DepositStatusPending                = 0
+	DepositStatusSuccess                = 1
+	DepositStatusCreditedCannotWithdraw = 6
+	DepositStatusWrongDeposit           = 7
+	DepositStatusWaitingUserConfirm     = 8
+)
+
+// Withdrawal statuses.
+const (
+	WithdrawStatusEmailSent        = 0
+	WithdrawStatusCancelled        = 1
+	WithdrawStatusAwaitingApproval = 2
+	WithdrawStatusRejected         = 3
+	WithdrawStatusProcessing       = 4
+	WithdrawStatusFailure          = 5
+	WithdrawStatusCompleted        = 6
+)
+
+// CapitalHistoryOptions -- filters of GetDepositHistory and GetWithdrawHistory. Zero values mean "not specified".
+// Without time range Binance returns records of the last 90 days, time range can't be wider than 90 days.
+type CapitalHistoryOptions struct {
+	Coin        string
+	Status      *int  // One of DepositStatus* or WithdrawStatus* constants
+	StartTimeMS int64 // 0 means not specified
+	EndTimeMS   int64 // 0 means not specified
+	Offset      int
+	Limit       int // Default 1000, maximum 1000
+}
+
+// DepositRecord -- single deposit.
+type DepositRecord struct {
+	Id            string  `json:"id"`
+	Amount        float64 `json:"amount,string"`
+	Coin          string  `json:"coin"`
+	Network       string  `json:"network"`
+	Status        int     `json:"status"`
+	Address       string  `json:"address"`
+	AddressTag    string  `json:"addressTag"`
+	TxId          string  `json:"txId"`
+	InsertTime    int64   `json:"insertTime"`
+	CompleteTime  int64   `json:"completeTime"`
+	TransferType  int     `json:"transferType"` // 0 - external transfer, 1 - internal transfer
+	ConfirmTimes  string  `json:"confirmTimes"` // Like "12/12"
+	UnlockConfirm int     `json:"unlockConfirm"`
+	WalletType    int     `json:"walletType"` // 0 - spot wallet, 1 - funding wallet
+}
+
+// WithdrawRecord -- single withdrawal. Note that ApplyTime and CompleteTime are date-time strings (UTC),
+// use ApplyTimeAsTime / CompleteTimeAsTime to parse them.
+type WithdrawRecord struct {
+	Id              string  `json:"id"`
+	Amount          float64 `json:"amount,string"`
+	TransactionFee  float64 `json:"transactionFee,string"`
+	Coin            string  `json:"coin"`
+	Network         string  `json:"network"`
+	Status          int     `json:"status"`
+	Address         string  `json:"address"`
+	AddressTag      string  `json:"addressTag"`
+	TxId            string  `json:"txId"`
+	ApplyTime       string  `json:"applyTime"`
+	CompleteTime    string  `json:"completeTime"`
+	TransferType    int     `json:"transferType"` // 0 - external transfer, 1 - internal transfer
+	WithdrawOrderId string  `json:"withdrawOrderId"`
+	Info            string  `json:"info"` // Reason of failure
+	ConfirmNo       int     `json:"confirmNo"`
+	WalletType      int     `json:"walletType"` // 0 - spot wallet, 1 - funding wallet
+	TxKey           string  `json:"txKey"`
+}
+
+// ApplyTimeAsTime parses ApplyTime of withdrawal.
+func (r WithdrawRecord) ApplyTimeAsTime() (time.Time, error) {
+	return time.Parse(binanceDateTimeLayout, r.ApplyTime)
+}
+
+// CompleteTimeAsTime parses CompleteTime of withdrawal. Returns zero time (without error) if withdrawal is not completed.
+func (r WithdrawRecord) CompleteTimeAsTime() (time.Time, error) {
+	if r.CompleteTime == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(binanceDateTimeLayout, r.CompleteTime)
+}
+
+// GetDepositHistory - deposit history of the account (SIGNED, SAPI).
+// Details: https://binance-docs.github.io/apidocs/spot/en/#deposit-history-supporting-network-user_data
+func (bc *BinanceClient) GetDepositHistory(opts CapitalHistoryOptions) ([]DepositRecord, Warning, error) {
+	var deposits []DepositRecord
+
+	warning, err := opts.addParams(bc.newSignedRequest(http.MethodGet)).
+		DoArray("/sapi/v1/capital/deposit/hisrec", 1, &deposits)
+
+	if err != nil || warning != nil {
+		return nil, warning, err
+	}
+
+	return deposits, nil, nil
+}
+
+// GetWithdrawHistory - withdrawal history of the account (SIGNED, SAPI).
+// Details: https://binance-docs.github.io/apidocs/spot/en/#withdraw-history-supporting-network-user_data
+func (bc *BinanceClient) GetWithdrawHistory(opts CapitalHistoryOptions) ([]WithdrawRecord, Warning, error) {
+	var withdrawals []WithdrawRecord
+
+	warning, err := opts.addParams(bc.newSignedRequest(http.MethodGet)).
+		DoArray("/sapi/v1/capital/withdraw/history", 1, &withdrawals)
+
+	if err != nil || warning != nil {
+		return nil, warning, err
+	}
+
+	return withdrawals, nil, nil
+}
+
+// addParams adds specified options to request (zero values mean "not specified").
+func (opts CapitalHistoryOptions) addParams(request *requestBuilder) *requestBuilder {
+	request.AddString("coin", opts.Coin)
+
+	if opts.Status != nil {
+		request.AddInt("status", *opts.Status, false)
+	}
+
+	if opts.StartTimeMS > 0 {
+		request.AddInt64("startTime", opts.StartTimeMS, false)
+	}
+
+	if opts.EndTimeMS > 0 {
+		request.AddInt64("endTime", opts.EndTimeMS, false)
+	}
+
+	if opts.Offset > 0 {
+		request.AddInt("offset", opts.Offset, false)
+	}
+
+	if opts.Limit > 0 {
+		request.AddInt("limit", opts.Limit, false)
+	}
+
+	return request
+}
@@ -0,0 +1,331 @@
+package bncclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const combinedStreamChannelSize = 100
+
+// StreamAggTrade -- "aggTrade" event of market stream.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/web-socket-streams.md#aggregate-trade-streams
+type StreamAggTrade struct {
+	EventType string `json:"e"`
+	EventTime int64  `json:"E"`
+	Symbol    string `json:"s"`
+	AggTrade
+}
+
+// StreamTrade -- "trade" event of market stream.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/web-socket-streams.md#trade-streams
+type StreamTrade struct {
+	EventType    string  `json:"e"`
+	EventTime    int64   `json:"E"`
+	Symbol       string  `json:"s"`
+	TradeId      int64   `json:"t"`
+	Price        float64 `json:"p,string"`
+	Qty          float64 `json:"q,string"`
+	Time         int64   `json:"T"`
+	IsBuyerMaker bool    `json:"m"`
+	Ignore       bool    `json:"M"`
+}
+
+// StreamBookTicker -- event of "bookTicker" market stream (best bid/ask update).
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/web-socket-streams.md#individual-symbol-book-ticker-streams
+type StreamBookTicker struct {
+	UpdateId int64   `json:"u"`
+	Symbol   string  `json:"s"`
+	BidPrice float64 `json:"b,string"`
+	BidQty   float64 `json:"B,string"`
+	AskPrice float64 `json:"a,string"`
+	AskQty   float64 `json:"A,string"`
+}
+
+// StreamMessage -- payload of stream which has no typed channel (for example "btcusdt@depth"), as it was received.
+type StreamMessage struct {
+	Stream string
+	Data   json.RawMessage
+}
+
+// CombinedStream -- single connection to many market streams (like "btcusdt@aggTrade", "ethusdt@bookTicker").
+// Payloads are demultiplexed by stream name: aggregated trades, trades and book tickers are delivered to typed channels,
+// payloads of all other streams - to Raw() channel. All channels should be read, because a full channel blocks the stream.
+// Subscriptions can be changed without reconnecting (see Subscribe, Unsubscribe).
+type CombinedStream struct {
+	conn         *websocket.Conn
+	client       *BinanceClient
+	aggTrades    chan StreamAggTrade
+	trades       chan StreamTrade
+	bookTickers  chan StreamBookTicker
+	raw          chan StreamMessage
+	errors       chan error
+	errorsMutex  sync.Mutex
+	errorsClosed bool
+	writeMutex   sync.Mutex // Websocket connection supports only one concurrent writer
+	nextId       int64
+	done         chan struct{} // Closed by Close()
+	closeOnce    sync.Once
+	closeErr     error
+}
+
+// StartCombinedStream - connects to combined stream and subscribes to given streams (can be empty, then use Subscribe).
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/web-socket-streams.md#general-wss-information
+// Stream names are like "<symbol>@<streamType>", symbol is converted to lowercase automatically.
+// Call Close() when the stream is not needed anymore.
+func (bc *BinanceClient) StartCombinedStream(streams []string) (*CombinedStream, error) {
+	if bc.lifecycle.isClosed() {
+		return nil, ErrClientClosed
+	}
+
+	streamURL := bc.streamBaseURL + "/stream"
+	if len(streams) > 0 {
+		streamURL += "?streams=" + strings.Join(normalizeStreamNames(streams), "/")
+	}
+
+	conn, _, err := bc.newWebsocketDialer().Dial(streamURL, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stream := &CombinedStream{
+		conn:        conn,
+		client:      bc,
+		aggTrades:   make(chan StreamAggTrade, combinedStreamChannelSize),
+		trades:      make(chan StreamTrade, combinedStreamChannelSize),
+		bookTickers: make(chan StreamBookTicker, combinedStreamChannelSize),
+		raw:         make(chan StreamMessage, combinedStreamChannelSize),
+		errors:      make(chan error, 1),
+		done:        make(chan struct{}),
+	}
+
+	bc.lifecycle.registerStream(stream)
+
+	go stream.readLoop()
+
+	select {
+	case <-bc.lifecycle.done: // Client was closed while the stream was starting, so Close() could miss it
+		_ = stream.Close()
+		return nil, ErrClientClosed
+	default:
+	}
+
+	return stream, nil
+}
+
+// normalizeStreamNames converts symbol part of stream names to lowercase (Binance requires it), stream type is kept as is.
+func normalizeStreamNames(streams []string) []string {
+	normalized := make([]string, len(streams))
+
+	for i, stream := range streams {
+		parts := strings.SplitN(stream, "@", 2)
+		parts[0] = strings.ToLower(parts[0])
+		normalized[i] = strings.Join(parts, "@")
+	}
+
+	return normalized
+}
+
+// Subscribe adds streams to the connection. Binance confirms subscription asynchronously, failed confirmation
+// is delivered to Errors() channel.
+func (s *CombinedStream) Subscribe(streams ...string) error {
+	return s.sendControlMessage("SUBSCRIBE", streams)
+}
+
+// Unsubscribe removes streams from the connection.
+func (s *CombinedStream) Unsubscribe(streams ...string) error {
+	return s.sendControlMessage("UNSUBSCRIBE", streams)
+}
+
+func (s *CombinedStream) sendControlMessage(method string, streams []string) error {
+	if len(streams) == 0 {
+		return errors.New("at least one stream should be specified")
+	}
+
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	s.nextId++
+
+	return s.conn.WriteJSON(struct {
+		Method string   `json:"method"`
+		Params []string `json:"params"`
+		Id     int64    `json:"id"`
+	}{Method: method, Params: normalizeStreamNames(streams), Id: s.nextId})
+}
+
+// AggTrades returns channel of aggregated trades. Channel is closed when stream stops.
+func (s *CombinedStream) AggTrades() <-chan StreamAggTrade {
+	return s.aggTrades
+}
+
+// Trades returns channel of trades. Channel is closed when stream stops.
+func (s *CombinedStream) Trades() <-chan StreamTrade {
+	return s.trades
+}
+
+// BookTickers returns channel of best bid/ask updates. Channel is closed when stream stops.
+func (s *CombinedStream) BookTickers() <-chan StreamBookTicker {
+	return s.bookTickers
+}
+
+// Raw returns channel of payloads of streams without typed channel. Channel is closed when stream stops.
+func (s *CombinedStream) Raw() <-chan StreamMessage {
+	return s.raw
+}
+
+// Errors returns channel of stream errors. Channel is closed when stream stops.
+func (s *CombinedStream) Errors() <-chan error {
+	return s.errors
+}
+
+// Close closes websocket connection. Safe to call several times. Streams are closed automatically by BinanceClient.Close() too.
+func (s *CombinedStream) Close() error {
+	s.closeOnce.Do(func() {
+		defer s.client.lifecycle.unregisterStream(s)
+		close(s.done)
+		_ = s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		s.closeErr = s.conn.Close()
+	})
+
+	return s.closeErr
+}
+
+func (s *CombinedStream) readLoop() {
+	defer close(s.aggTrades)
+	defer close(s.trades)
+	defer close(s.bookTickers)
+	defer close(s.raw)
+	defer s.closeErrors()
+
+	for {
+		_, message, err := s.conn.ReadMessage()
+
+		if err != nil {
+			select {
+			case <-s.done: // Connection was closed by Close(), it's not an error
+			default:
+				s.reportError(err)
+			}
+			return
+		}
+
+		var envelope struct {
+			Stream string          `json:"stream"`
+			Data   json.RawMessage `json:"data"`
+			Id     *int64          `json:"id"`
+			Error  *struct {
+				Code int    `json:"code"`
+				Msg  string `json:"msg"`
+			} `json:"error"`
+		}
+
+		if err := json.Unmarshal(message, &envelope); err != nil {
+			s.reportError(err)
+			continue
+		}
+
+		if envelope.Stream == "" { // Response to control message
+			if envelope.Error != nil {
+				s.reportError(fmt.Errorf("control message %d failed: %w", derefInt64(envelope.Id), binanceError{Code: envelope.Error.Code, Msg: envelope.Error.Msg}))
+			}
+			continue
+		}
+
+		if !s.route(envelope.Stream, envelope.Data) {
+			return
+		}
+	}
+}
+
+// route decodes payload according to stream type and sends it to the corresponding channel.
+// Returns false if the stream was closed while waiting for the channel.
+func (s *CombinedStream) route(stream string, data json.RawMessage) bool {
+	streamType := ""
+	if parts := strings.SplitN(stream, "@", 2); len(parts) == 2 {
+		streamType = parts[1]
+	}
+
+	switch streamType {
+	case "aggTrade":
+		var aggTrade StreamAggTrade
+		if err := json.Unmarshal(data, &aggTrade); err != nil {
+			s.reportError(err)
+			return true
+		}
+		select {
+		case s.aggTrades <- aggTrade:
+		case <-s.done:
+			return false
+		}
+
+	case "trade":
+		var trade StreamTrade
+		if err := json.Unmarshal(data, &trade); err != nil {
+			s.reportError(err)
+			return true
+		}
+		select {
+		case s.trades <- trade:
+		case <-s.done:
+			return false
+		}
+
+	case "bookTicker":
+		var bookTicker StreamBookTicker
+		if err := json.Unmarshal(data, &bookTicker); err != nil {
+			s.reportError(err)
+			return true
+		}
+		select {
+		case s.bookTickers <- bookTicker:
+		case <-s.done:
+			return false
+		}
+
+	default:
+		select {
+		case s.raw <- StreamMessage{Stream: stream, Data: data}:
+		case <-s.done:
+			return false
+		}
+	}
+
+	return true
+}
+
+// reportError sends error to errors channel without blocking. If previous error was not read yet, the new one is dropped.
+func (s *CombinedStream) reportError(err error) {
+	s.errorsMutex.Lock()
+	defer s.errorsMutex.Unlock()
+
+	if s.errorsClosed {
+		return
+	}
+
+	select {
+	case s.errors <- err:
+	default:
+	}
+}
+
+func (s *CombinedStream) closeErrors() {
+	s.errorsMutex.Lock()
+	defer s.errorsMutex.Unlock()
+
+	s.errorsClosed = true
+	close(s.errors)
+}
+
+func derefInt64(value *int64) int64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
@@ -0,0 +1,122 @@
+package bncclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// depthResponse -- order book response with given number of levels on every side, like /api/v3/depth?limit=5000.
+func depthResponse(levels int) string {
+	var body strings.Builder
+
+	writeSide := func(basePrice float64, step float64) {
+		for i := 0; i < levels; i++ {
+			if i > 0 {
+				body.WriteString(",")
+			}
+			fmt.Fprintf(&body, `["%.8f","%.8f"]`, basePrice+step*float64(i), float64(i%97)+0.12345678)
+		}
+	}
+
+	body.WriteString(`{"lastUpdateId":1027024,"bids":[`)
+	writeSide(30000, -0.01)
+	body.WriteString(`],"asks":[`)
+	writeSide(30000.01, 0.01)
+	body.WriteString(`]}`)
+
+	return body.String()
+}
+
+// depthDoer -- answers every request with depth response, compressed if compress is true and the request accepts gzip.
+// Size of the last sent body (bytes over the wire) is kept in sentBytes.
+type depthDoer struct {
+	body      []byte
+	compress  bool
+	sentBytes int
+}
+
+func (d *depthDoer) Do(request *http.Request) (*http.Response, error) {
+	header := http.Header{}
+	body := d.body
+
+	if d.compress && strings.Contains(request.Header.Get("Accept-Encoding"), "gzip") {
+		var compressed bytes.Buffer
+		gzipWriter := gzip.NewWriter(&compressed)
+		_, _ = gzipWriter.Write(d.body)
+		_ = gzipWriter.Close()
+
+		header.Set("Content-Encoding", "gzip")
+		body = compressed.Bytes()
+	}
+
+	d.sentBytes = len(body)
+
+	return &http.Response{StatusCode: 200, Header: header, Body: io.NopCloser(bytes.NewReader(body)), Request: request}, nil
+}
+
+// emptyWeightController -- weight controller of its own with empty window, so test requests are not throttled
+// and don't affect the shared one.
+func emptyWeightController() *weightController {
+	return newWeightController()
+}
+
+func newUnlimitedClient(doer Doer) *BinanceClient {
+	bc := NewBinanceClient("test-api-key")
+	bc.weightController = emptyWeightController()
+	bc.SetHTTPClient(doer)
+
+	return bc
+}
+
+func TestGetOrderBookGzipResponse(t *testing.T) {
+	doer := &depthDoer{body: []byte(depthResponse(5000)), compress: true}
+	bc := newUnlimitedClient(doer)
+
+	orderBook, warning, err := bc.GetOrderBook("BTCUSDT", 5000)
+	if err != nil || warning != nil {
+		t.Fatalf("unexpected warning %v or error %v", warning, err)
+	}
+
+	if len(orderBook.Bids) != 5000 || len(orderBook.Asks) != 5000 {
+		t.Fatalf("expected 5000 levels on every side, got %d bids and %d asks", len(orderBook.Bids), len(orderBook.Asks))
+	}
+
+	if doer.sentBytes >= len(doer.body) {
+		t.Fatalf("expected compressed response, %d bytes sent", doer.sentBytes)
+	}
+}
+
+// BenchmarkGetOrderBook5000 compares bytes sent over the wire for depth=5000 with and without gzip
+// (see wire-bytes/op metric).
+func BenchmarkGetOrderBook5000(b *testing.B) {
+	for _, compress := range []bool{false, true} {
+		name := "identity"
+		if compress {
+			name = "gzip"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			doer := &depthDoer{body: []byte(depthResponse(5000)), compress: compress}
+			bc := newUnlimitedClient(doer)
+			sentBytes := 0
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				bc.weightController = emptyWeightController() // Benchmark is not limited by weight
+
+				if _, warning, err := bc.GetOrderBook("BTCUSDT", 5000); err != nil || warning != nil {
+					b.Fatalf("unexpected warning %v or error %v", warning, err)
+				}
+				sentBytes += doer.sentBytes
+			}
+
+			b.ReportMetric(float64(sentBytes)/float64(b.N), "wire-bytes/op")
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package bncclient
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const dryRunMaxRecordedRequests = 1000 // Older requests are dropped from the log
+
+// dryRunArrayPaths -- endpoints which respond with JSON array, default canned response for them is empty array "[]".
+// Book ticker responds with array only if symbol is not specified.
+var dryRunArrayPaths = map[string]bool{
+	"/api/v3/aggTrades":                 true,
+	"/api/v3/trades":                    true,
+	"/api/v3/historicalTrades":          true,
+	"/api/v3/klines":                    true,
+	"/api/v3/uiKlines":                  true,
+	"/api/v3/openOrders":                true,
+	"/api/v3/myPreventedMatches":        true,
+	"/sapi/v1/capital/deposit/hisrec":   true,
+	"/sapi/v1/capital/withdraw/history": true,
+}
+
+// RecordedRequest -- request which would be sent to Binance, if dry-run mode was off. Signed requests are recorded
+// signed: QueryParams include timestamp, recvWindow and signature, and RawQuery is exactly the query string
+// which would be sent (signature is calculated over it).
+type RecordedRequest struct {
+	Method      string
+	Path        string
+	QueryParams map[string]string
+	RawQuery    string
+	Weight      int
+	Time        time.Time
+}
+
+// dryRunRecorder keeps settings of dry-run mode and log of recorded requests.
+type dryRunRecorder struct {
+	enabled         bool
+	includeReads    bool
+	cannedResponses map[string]string // Keyed by path
+	requests        []RecordedRequest
+	mutex           sync.Mutex
+}
+
+// SetDryRun - when enabled, write requests (POST, PUT, DELETE) are not sent to Binance. Instead, they are recorded
+// (see LastRequests) and a canned response is returned (see SetDryRunResponse, default response is empty JSON object "{}",
+// or empty array "[]" for endpoints which respond with array).
+// If includeReads is true, read (GET) requests are recorded and answered with canned responses too.
+// Requests in dry-run mode are not accounted in weight controller.
+func (bc *BinanceClient) SetDryRun(enabled bool, includeReads bool) {
+	bc.dryRun.mutex.Lock()
+	defer bc.dryRun.mutex.Unlock()
+
+	bc.dryRun.enabled = enabled
+	bc.dryRun.includeReads = includeReads
+}
+
+// SetDryRunResponse - sets canned JSON response returned in dry-run mode for given path (like "/api/v3/order").
+func (bc *BinanceClient) SetDryRunResponse(path string, jsonResponse string) {
+	bc.dryRun.mutex.Lock()
+	defer bc.dryRun.mutex.Unlock()
+
+	bc.dryRun.cannedResponses[path] = jsonResponse
+}
+
+// LastRequests - returns requests recorded in dry-run mode (up to 1000 most recent), oldest first.
+func (bc *BinanceClient) LastRequests() []RecordedRequest {
+	bc.dryRun.mutex.Lock()
+	defer bc.dryRun.mutex.Unlock()
+
+	requests := make([]RecordedRequest, len(bc.dryRun.requests))
+	copy(requests, bc.dryRun.requests)
+
+	return requests
+}
+
+func newDryRunRecorder() *dryRunRecorder {
+	return &dryRunRecorder{cannedResponses: make(map[string]string)}
+}
+
+// interceptsWrites returns true if write requests are not sent now.
+func (r *dryRunRecorder) interceptsWrites() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.enabled
+}
+
+// intercepts returns true if request with given method should not be sent in dry-run mode.
+func (r *dryRunRecorder) intercepts(method string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.enabled && (method != http.MethodGet || r.includeReads)
+}
+
+// record records request (rawQuery is query string as it would be sent, signed if needed) and returns canned response.
+func (r *dryRunRecorder) record(method string, path string, rawQuery string, weight int) []byte {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	queryParams := make(map[string]string)
+	if parsedQuery, err := url.ParseQuery(rawQuery); err == nil {
+		for key := range parsedQuery {
+			queryParams[key] = parsedQuery.Get(key)
+		}
+	}
+
+	r.requests = append(r.requests, RecordedRequest{
+		Method:      method,
+		Path:        path,
+		QueryParams: queryParams,
+		RawQuery:    rawQuery,
+		Weight:      weight,
+		Time:        time.Now(),
+	})
+
+	if len(r.requests) > dryRunMaxRecordedRequests {
+		r.requests = r.requests[len(r.requests)-dryRunMaxRecordedRequests:]
+	}
+
+	cannedResponse, exists := r.cannedResponses[path]
+	if !exists {
+		cannedResponse = defaultDryRunResponse(path, queryParams)
+	}
+
+	return []byte(cannedResponse)
+}
+
+// defaultDryRunResponse returns empty JSON array for endpoints which respond with array, and empty object for the rest.
+func defaultDryRunResponse(path string, queryParams map[string]string) string {
+	if dryRunArrayPaths[path] || (path == "/api/v3/ticker/bookTicker" && queryParams["symbol"] == "") {
+		return "[]"
+	}
+
+	return "{}"
+}
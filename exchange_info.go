@@ -0,0 +1,432 @@
+package bncclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultExchangeInfoTTL = time.Hour
+
+// ExchangeInfo -- current exchange trading rules and symbol information.
+type ExchangeInfo struct {
+	Timezone   string       `json:"timezone"`
+	ServerTime int64        `json:"serverTime"`
+	RateLimits []RateLimit  `json:"rateLimits"`
+	Symbols    []SymbolInfo `json:"symbols"`
+}
+
+// RateLimit -- one of rate limits applied by Binance. RateLimitType is one of REQUEST_WEIGHT, ORDERS, RAW_REQUESTS,
+// Interval is one of SECOND, MINUTE, HOUR, DAY. For example, {REQUEST_WEIGHT, MINUTE, 1, 1200} means 1200 weight per 1 minute.
+type RateLimit struct {
+	RateLimitType string `json:"rateLimitType"`
+	Interval      string `json:"interval"`
+	IntervalNum   int    `json:"intervalNum"`
+	Limit         int    `json:"limit"`
+}
+
+type SymbolInfo struct {
+	Symbol                 string         `json:"symbol"`
+	Status                 string         `json:"status"`
+	BaseAsset              string         `json:"baseAsset"`
+	BaseAssetPrecision     int            `json:"baseAssetPrecision"`
+	QuoteAsset             string         `json:"quoteAsset"`
+	QuoteAssetPrecision    int            `json:"quoteAssetPrecision"`
+	OrderTypes             []string       `json:"orderTypes"`
+	IcebergAllowed         bool           `json:"icebergAllowed"`
+	OcoAllowed             bool           `json:"ocoAllowed"`
+	IsSpotTradingAllowed   bool           `json:"isSpotTradingAllowed"`
+	IsMarginTradingAllowed bool           `json:"isMarginTradingAllowed"`
+	Filters                []SymbolFilter `json:"filters"`
+	Permissions            []string       `json:"permissions"`
+}
+
+// SymbolFilter -- trading rule of a symbol. Which fields are filled depends on FilterType
+// (PRICE_FILTER: MinPrice, MaxPrice, TickSize; LOT_SIZE and MARKET_LOT_SIZE: MinQty, MaxQty, StepSize;
+// MIN_NOTIONAL and NOTIONAL: MinNotional, MaxNotional etc).
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/filters.md
+type SymbolFilter struct {
+	FilterType       string  `json:"filterType"`
+	MinPrice         float64 `json:"minPrice,string"`
+	MaxPrice         float64 `json:"maxPrice,string"`
+	TickSize         float64 `json:"tickSize,string"`
+	MultiplierUp     float64 `json:"multiplierUp,string"`
+	MultiplierDown   float64 `json:"multiplierDown,string"`
+	MinQty           float64 `json:"minQty,string"`
+	MaxQty           float64 `json:"maxQty,string"`
+	StepSize         float64 `json:"stepSize,string"`
+	MinNotional      float64 `json:"minNotional,string"`
+	MaxNotional      float64 `json:"maxNotional,string"`
+	ApplyToMarket    bool    `json:"applyToMarket"`
+	ApplyMinToMarket bool    `json:"applyMinToMarket"`
+	ApplyMaxToMarket bool    `json:"applyMaxToMarket"`
+	AvgPriceMins     int     `json:"avgPriceMins"`
+	Limit            int     `json:"limit"`
+	MaxNumOrders     int     `json:"maxNumOrders"`
+	MaxNumAlgoOrders int     `json:"maxNumAlgoOrders"`
+}
+
+// rateLimitIntervals -- duration of one unit of every RateLimit.Interval.
+var rateLimitIntervals = map[string]time.Duration{
+	"SECOND": time.Second,
+	"MINUTE": time.Minute,
+	"HOUR":   time.Hour,
+	"DAY":    24 * time.Hour,
+}
+
+// Duration returns the window of rate limit, for example 1 minute for {Interval: MINUTE, IntervalNum: 1}.
+// Returns 0 for unknown interval.
+func (rl RateLimit) Duration() time.Duration {
+	return rateLimitIntervals[rl.Interval] * time.Duration(rl.IntervalNum)
+}
+
+// exchangeInfoCache -- cached copy of exchangeInfo with its expiration time and HTTP validators (ETag, Last-Modified).
+// The mutex is never held during the request: the first caller which finds the cache expired makes the request
+// (inFlight), and concurrent callers wait for its result instead of making their own requests.
+type exchangeInfoCache struct {
+	exchangeInfo ExchangeInfo
+	isSet        bool
+	expiresAt    time.Time
+	etag         string
+	lastModified string
+	ttl          time.Duration
+	inFlight     *exchangeInfoFetch
+	mutex        sync.Mutex
+}
+
+// exchangeInfoFetch -- request of exchangeInfo in flight, done is closed when its result is set.
+type exchangeInfoFetch struct {
+	done         chan struct{}
+	exchangeInfo ExchangeInfo
+	warning      Warning
+	err          error
+}
+
+func newExchangeInfoCache() *exchangeInfoCache {
+	return &exchangeInfoCache{ttl: defaultExchangeInfoTTL}
+}
+
+// SetExchangeInfoTTL - sets how long GetExchangeInfo returns cached copy without network round trip (default 1 hour).
+// If Binance sends Cache-Control max-age header, it takes precedence over TTL. Zero TTL disables caching.
+func (bc *BinanceClient) SetExchangeInfoTTL(ttl time.Duration) {
+	bc.exchangeInfoCache.mutex.Lock()
+	defer bc.exchangeInfoCache.mutex.Unlock()
+
+	bc.exchangeInfoCache.ttl = ttl
+	bc.exchangeInfoCache.expiresAt = time.Time{}
+}
+
+// RefreshExchangeInfo - the same as GetExchangeInfo, but ignores cache expiration and always asks Binance
+// (if the request is already in flight, its result is returned).
+func (bc *BinanceClient) RefreshExchangeInfo() (ExchangeInfo, Warning, error) {
+	bc.exchangeInfoCache.mutex.Lock()
+	bc.exchangeInfoCache.expiresAt = time.Time{}
+	bc.exchangeInfoCache.mutex.Unlock()
+
+	return bc.GetExchangeInfo()
+}
+
+// GetExchangeInfo - Current exchange trading rules and symbol information.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#exchange-information
+// Response is cached (see SetExchangeInfoTTL): until cache expires, cached copy is returned without network round trip
+// and without charging weight. Returned value shares slices with the cache, so treat it as read-only.
+// Concurrent calls with expired cache share one request (waiting for it stops when context of the client is cancelled).
+func (bc *BinanceClient) GetExchangeInfo() (ExchangeInfo, Warning, error) {
+	cache := bc.exchangeInfoCache
+
+	cache.mutex.Lock()
+
+	if cache.isSet && time.Now().Before(cache.expiresAt) {
+		exchangeInfo := cache.exchangeInfo
+		cache.mutex.Unlock()
+		return exchangeInfo, nil, nil
+	}
+
+	if fetch := cache.inFlight; fetch != nil {
+		cache.mutex.Unlock()
+
+		select {
+		case <-fetch.done:
+			return fetch.exchangeInfo, fetch.warning, fetch.err
+		case <-bc.context().Done():
+			return ExchangeInfo{}, nil, bc.context().Err()
+		}
+	}
+
+	fetch := &exchangeInfoFetch{done: make(chan struct{})}
+	cache.inFlight = fetch
+	isSet, etag, lastModified := cache.isSet, cache.etag, cache.lastModified
+
+	cache.mutex.Unlock()
+
+	defer close(fetch.done)
+
+	request := bc.newRequest(http.MethodGet)
+
+	// If we have cached copy, ask Binance to send the body only if it was modified:
+	if isSet {
+		request.WithHeader("If-None-Match", etag).WithHeader("If-Modified-Since", lastModified)
+	}
+
+	exchangeInfoRaw, statusCode, responseHeaders, warning, err := request.DoRaw("/api/v3/exchangeInfo", 20)
+
+	var exchangeInfo ExchangeInfo
+	if err == nil && warning == nil && statusCode != http.StatusNotModified {
+		err = bc.tryParseResponse(exchangeInfoRaw, &exchangeInfo)
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.inFlight = nil
+
+	if err != nil || warning != nil {
+		fetch.warning, fetch.err = warning, err
+		return ExchangeInfo{}, warning, err
+	}
+
+	if statusCode != http.StatusNotModified {
+		cache.exchangeInfo = exchangeInfo
+		cache.isSet = true
+		cache.etag = responseHeaders.Get("ETag")
+		cache.lastModified = responseHeaders.Get("Last-Modified")
+	}
+
+	cache.expiresAt = time.Now().Add(cacheTTLFromHeaders(responseHeaders, cache.ttl))
+	fetch.exchangeInfo = cache.exchangeInfo
+
+	return cache.exchangeInfo, nil, nil
+}
+
+// knownPermissions -- account types accepted by "permissions" parameter of exchangeInfo (besides TRD_GRP_* trading groups).
+var knownPermissions = map[string]bool{
+	"SPOT":      true,
+	"MARGIN":    true,
+	"LEVERAGED": true,
+}
+
+// GetExchangeInfoForPermissions - the same as GetExchangeInfo, but only symbols tradable with any of given permissions
+// (like "SPOT", "MARGIN") are returned, so the response is smaller. Response is not cached.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#exchange-information
+func (bc *BinanceClient) GetExchangeInfoForPermissions(permissions []string) (ExchangeInfo, Warning, error) {
+	if len(permissions) == 0 {
+		return ExchangeInfo{}, nil, errors.New("at least one permission is required")
+	}
+
+	for _, permission := range permissions {
+		if !knownPermissions[permission] && !strings.HasPrefix(permission, "TRD_GRP_") {
+			return ExchangeInfo{}, nil, errors.New(fmt.Sprintf("Not allowed permission: %s", permission))
+		}
+	}
+
+	permissionsJSON, err := json.Marshal(permissions)
+	if err != nil {
+		return ExchangeInfo{}, nil, err
+	}
+
+	var exchangeInfo ExchangeInfo
+
+	warning, err := bc.newRequest(http.MethodGet).
+		AddString("permissions", string(permissionsJSON)).
+		Do("/api/v3/exchangeInfo", 20, &exchangeInfo)
+
+	if err != nil || warning != nil {
+		return ExchangeInfo{}, warning, err
+	}
+
+	return exchangeInfo, nil, nil
+}
+
+// cacheTTLFromHeaders returns max-age from Cache-Control header (no-cache/no-store mean 0), or defaultTTL if there is no such header.
+func cacheTTLFromHeaders(header http.Header, defaultTTL time.Duration) time.Duration {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.TrimSpace(strings.ToLower(directive))
+
+		if directive == "no-cache" || directive == "no-store" {
+			return 0
+		}
+
+		if strings.HasPrefix(directive, "max-age=") {
+			if maxAge, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil {
+				return time.Duration(maxAge) * time.Second
+			}
+		}
+	}
+
+	return defaultTTL
+}
+
+// ApplyRateLimitsFromExchangeInfo - fetches exchangeInfo and configures weight controller's limit and window
+// from its REQUEST_WEIGHT rate limit, instead of default 1200 weight per minute.
+// Note: weight controller is shared by all clients, so limits are changed for all of them.
+func (bc *BinanceClient) ApplyRateLimitsFromExchangeInfo() (Warning, error) {
+	exchangeInfo, warning, err := bc.GetExchangeInfo()
+
+	if err != nil || warning != nil {
+		return warning, err
+	}
+
+	return nil, bc.ApplyRateLimits(exchangeInfo.RateLimits)
+}
+
+// ApplyRateLimits - configures weight controller's limit and window from REQUEST_WEIGHT entry of given rate limits.
+// If there are several REQUEST_WEIGHT entries, the one with the shortest window is applied.
+// ORDERS entries (if any) replace default order rate limits used by PlaceOrder, PlaceOCOOrder and CancelReplaceOrder.
+func (bc *BinanceClient) ApplyRateLimits(rateLimits []RateLimit) error {
+	bc.orderRateController.setLimits(rateLimits)
+
+	var requestWeightLimit *RateLimit
+
+	for i, rateLimit := range rateLimits {
+		if rateLimit.RateLimitType != "REQUEST_WEIGHT" || rateLimit.Duration() <= 0 || rateLimit.Limit <= 0 {
+			continue
+		}
+
+		if requestWeightLimit == nil || rateLimit.Duration() < requestWeightLimit.Duration() {
+			requestWeightLimit = &rateLimits[i]
+		}
+	}
+
+	if requestWeightLimit == nil {
+		return errors.New(fmt.Sprintf("No valid REQUEST_WEIGHT rate limit found among %d rate limits", len(rateLimits)))
+	}
+
+	bc.weightController.setLimits(requestWeightLimit.Limit, requestWeightLimit.Duration().Milliseconds())
+
+	return nil
+}
+
+// GetTradingSymbols - returns names of symbols which are currently traded (status TRADING).
+// Exchange info is taken from cache, if it's fresh (see GetExchangeInfo).
+func (bc *BinanceClient) GetTradingSymbols() ([]string, Warning, error) {
+	exchangeInfo, warning, err := bc.GetExchangeInfo()
+
+	if err != nil || warning != nil {
+		return nil, warning, err
+	}
+
+	symbols := make([]string, 0, len(exchangeInfo.Symbols))
+	for _, symbolInfo := range exchangeInfo.Symbols {
+		if symbolInfo.Status == "TRADING" {
+			symbols = append(symbols, symbolInfo.Symbol)
+		}
+	}
+
+	return symbols, nil, nil
+}
+
+// GetSymbolInfo returns information about the symbol, second value is false if there is no such symbol.
+func (ei ExchangeInfo) GetSymbolInfo(symbol string) (SymbolInfo, bool) {
+	for _, symbolInfo := range ei.Symbols {
+		if symbolInfo.Symbol == symbol {
+			return symbolInfo, true
+		}
+	}
+
+	return SymbolInfo{}, false
+}
+
+// GetFilter returns filter of given type (like "LOT_SIZE"), second value is false if symbol has no such filter.
+func (si SymbolInfo) GetFilter(filterType string) (SymbolFilter, bool) {
+	for _, filter := range si.Filters {
+		if filter.FilterType == filterType {
+			return filter, true
+		}
+	}
+
+	return SymbolFilter{}, false
+}
+
+// RoundPrice snaps price to the nearest multiple of PRICE_FILTER tickSize.
+// If symbol has no PRICE_FILTER (or tickSize is 0), price is returned as is.
+func (si SymbolInfo) RoundPrice(price float64) float64 {
+	priceFilter, exists := si.GetFilter("PRICE_FILTER")
+	if !exists {
+		return price
+	}
+
+	return snapToStep(price, priceFilter.TickSize, math.Round)
+}
+
+// RoundQty snaps quantity DOWN to the multiple of LOT_SIZE stepSize (rounding down guarantees we never try
+// to sell/buy more than we have). If symbol has no LOT_SIZE (or stepSize is 0), quantity is returned as is.
+func (si SymbolInfo) RoundQty(qty float64) float64 {
+	lotSizeFilter, exists := si.GetFilter("LOT_SIZE")
+	if !exists {
+		return qty
+	}
+
+	return snapToStep(qty, lotSizeFilter.StepSize, math.Floor)
+}
+
+// ConvertQuoteToBase converts amount of quote asset (like "spend 100 USDT") to quantity of base asset at given price,
+// rounded DOWN to LOT_SIZE stepSize (so the order never spends more than quoteQty). Returns 0 if price is not positive.
+func ConvertQuoteToBase(quoteQty float64, price float64, symbolInfo SymbolInfo) float64 {
+	if price <= 0 {
+		return 0
+	}
+
+	return symbolInfo.RoundQty(quoteQty / price)
+}
+
+// ConvertBaseToQuote is inverse of ConvertQuoteToBase: base quantity is rounded DOWN to LOT_SIZE stepSize first (as it
+// would be in the order), then converted to quote amount, which is rounded DOWN to quote asset precision.
+func ConvertBaseToQuote(baseQty float64, price float64, symbolInfo SymbolInfo) float64 {
+	quoteQty := symbolInfo.RoundQty(baseQty) * price
+
+	if symbolInfo.QuoteAssetPrecision <= 0 {
+		return quoteQty
+	}
+
+	return snapToStep(quoteQty, math.Pow(10, -float64(symbolInfo.QuoteAssetPrecision)), math.Floor)
+}
+
+// ValidateNotional checks that notional value of order (price * qty) satisfies MIN_NOTIONAL or NOTIONAL filter.
+// Returns error wrapping ErrFilterFailure, if it doesn't.
+func (si SymbolInfo) ValidateNotional(price float64, qty float64) error {
+	notional := price * qty
+
+	for _, filterType := range []string{"MIN_NOTIONAL", "NOTIONAL"} {
+		filter, exists := si.GetFilter(filterType)
+		if !exists {
+			continue
+		}
+
+		if notional < filter.MinNotional {
+			return fmt.Errorf("%w: %s: notional %v of %s order is less than minimum %v", ErrFilterFailure, filterType, notional, si.Symbol, filter.MinNotional)
+		}
+
+		if filter.MaxNotional > 0 && notional > filter.MaxNotional {
+			return fmt.Errorf("%w: %s: notional %v of %s order is greater than maximum %v", ErrFilterFailure, filterType, notional, si.Symbol, filter.MaxNotional)
+		}
+	}
+
+	return nil
+}
+
+// snapToStep rounds value to the multiple of step with given rounding function (math.Round, math.Floor).
+// The result is additionally rounded to decimal places of step, to get rid of float artifacts like 0.30000000000000004.
+func snapToStep(value float64, step float64, roundFn func(float64) float64) float64 {
+	if step <= 0 {
+		return value
+	}
+
+	const epsilon = 1e-9 // Protects from cases like 0.3/0.1 = 2.9999999999999996, which should be 3 steps
+	steps := roundFn(value/step + epsilon)
+
+	decimals := 0
+	stepStr := strconv.FormatFloat(step, 'f', -1, 64)
+	if dotIndex := strings.IndexByte(stepStr, '.'); dotIndex >= 0 {
+		decimals = len(stepStr) - dotIndex - 1
+	}
+
+	multiplier := math.Pow(10, float64(decimals))
+
+	return math.Round(steps*step*multiplier) / multiplier
+}
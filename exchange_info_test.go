@@ -0,0 +1,91 @@
+package bncclient
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetExchangeInfoSingleFlight(t *testing.T) {
+	var requestsCount int32
+	requestStarted := make(chan struct{}, 1)
+	releaseResponse := make(chan struct{})
+
+	bc := newUnlimitedClient(doerFunc(func(request *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requestsCount, 1)
+		requestStarted <- struct{}{}
+		<-releaseResponse
+		return jsonResponse(request, `{"timezone":"UTC","symbols":[{"symbol":"BTCUSDT"}]}`), nil
+	}))
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make(chan ExchangeInfo, callers)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		exchangeInfo, _, _ := bc.GetExchangeInfo()
+		results <- exchangeInfo
+	}()
+
+	<-requestStarted
+
+	// The cache mutex is not held during the request, so other callers are not blocked on it:
+	bc.exchangeInfoCache.mutex.Lock()
+	inFlight := bc.exchangeInfoCache.inFlight != nil
+	bc.exchangeInfoCache.mutex.Unlock()
+
+	if !inFlight {
+		t.Fatal("expected the request to be in flight")
+	}
+
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			exchangeInfo, _, _ := bc.GetExchangeInfo()
+			results <- exchangeInfo
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond) // Let the callers join the request in flight
+	close(releaseResponse)
+	wg.Wait()
+	close(results)
+
+	for exchangeInfo := range results {
+		if len(exchangeInfo.Symbols) != 1 || exchangeInfo.Symbols[0].Symbol != "BTCUSDT" {
+			t.Fatalf("unexpected exchange info: %+v", exchangeInfo)
+		}
+	}
+
+	if count := atomic.LoadInt32(&requestsCount); count != 1 {
+		t.Fatalf("expected 1 request, got %d", count)
+	}
+}
+
+func TestRefreshExchangeInfoIgnoresCache(t *testing.T) {
+	var requestsCount int32
+
+	bc := newUnlimitedClient(doerFunc(func(request *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requestsCount, 1)
+		return jsonResponse(request, `{"timezone":"UTC","symbols":[]}`), nil
+	}))
+
+	for i := 0; i < 3; i++ {
+		if _, _, err := bc.GetExchangeInfo(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, _, err := bc.RefreshExchangeInfo(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if count := atomic.LoadInt32(&requestsCount); count != 2 {
+		t.Fatalf("expected 2 requests (first one and refresh), got %d", count)
+	}
+}
@@ -1,3 +1,5 @@
 module github.com/anxp/bncclient
 
 go 1.16
+
+require github.com/gorilla/websocket v1.5.0
@@ -0,0 +1,128 @@
+package bncclient
+
+import (
+	"sort"
+	"sync"
+)
+
+// KeySelectionStrategy -- how the client picks API key from the pool for the next request.
+type KeySelectionStrategy int
+
+const (
+	// RoundRobin -- keys are used in turn. If the next key has no available weight, the following one is tried.
+	RoundRobin KeySelectionStrategy = iota
+	// LeastLoaded -- key with the smallest used share of its weight limit is used.
+	LeastLoaded
+)
+
+// keyPool -- set of API keys, each one with its own weight controller (Binance accounts weight per IP/key).
+type keyPool struct {
+	keys     []pooledKey
+	strategy KeySelectionStrategy
+	next     int
+	mutex    sync.Mutex
+}
+
+type pooledKey struct {
+	apiKey           string
+	weightController *weightController
+}
+
+// AddKey - adds API key to the client's key pool, requests are then spread between the client's own key and added keys.
+// Every added key has its own weight controller, so with N keys (used from different IPs) the client can make N times
+// more requests. Instead of returning a Warning when the current key's budget is exhausted, the client picks the next key
+// with available budget. See SetKeySelectionStrategy.
+func (bc *BinanceClient) AddKey(apiKey string) {
+	if bc.keyPool == nil {
+		bc.keyPool = &keyPool{
+			keys: []pooledKey{{apiKey: bc.apiKey, weightController: bc.weightController}},
+		}
+	}
+
+	bc.keyPool.mutex.Lock()
+	defer bc.keyPool.mutex.Unlock()
+
+	bc.keyPool.keys = append(bc.keyPool.keys, pooledKey{apiKey: apiKey, weightController: newWeightController()})
+}
+
+// SetKeySelectionStrategy - sets how the key is picked from the pool: RoundRobin (default) or LeastLoaded.
+// Has no effect until at least one key was added with AddKey.
+func (bc *BinanceClient) SetKeySelectionStrategy(strategy KeySelectionStrategy) {
+	if bc.keyPool == nil {
+		return
+	}
+
+	bc.keyPool.mutex.Lock()
+	defer bc.keyPool.mutex.Unlock()
+
+	bc.keyPool.strategy = strategy
+}
+
+// acquireKey -- picks API key for the request and accounts request weight in the weight controller of that key.
+// Returns the key and recommended sleep time (ms). Sleep time is greater than 0 only if no key has available budget,
+// in this case it's the shortest wait among all keys.
+func (bc *BinanceClient) acquireKey(apiKey string, weight int, highPriority bool) (string, int64) {
+	// Key pool is used only for requests made with the client's own key, explicitly given keys are accounted as before:
+	if bc.keyPool == nil || apiKey != bc.apiKey {
+		return apiKey, bc.weightController.getSleepTime(weight, highPriority)
+	}
+
+	return bc.keyPool.acquire(weight, highPriority)
+}
+
+func (kp *keyPool) acquire(weight int, highPriority bool) (string, int64) {
+	kp.mutex.Lock()
+	candidates := make([]pooledKey, 0, len(kp.keys))
+
+	switch kp.strategy {
+	case LeastLoaded:
+		candidates = append(candidates, kp.keys...)
+		sort.SliceStable(candidates, func(i, j int) bool {
+			usedI, limitI := candidates[i].weightController.usage()
+			usedJ, limitJ := candidates[j].weightController.usage()
+			return float64(usedI)/float64(limitI) < float64(usedJ)/float64(limitJ)
+		})
+	default: // RoundRobin
+		for i := 0; i < len(kp.keys); i++ {
+			candidates = append(candidates, kp.keys[(kp.next+i)%len(kp.keys)])
+		}
+		kp.next = (kp.next + 1) % len(kp.keys)
+	}
+	kp.mutex.Unlock()
+
+	minSleepTimeMS := int64(-1)
+
+	for _, candidate := range candidates {
+		sleepTimeMS := candidate.weightController.getSleepTime(weight, highPriority) // Doesn't account weight if the budget is exhausted
+		if sleepTimeMS == 0 {
+			return candidate.apiKey, 0
+		}
+
+		if minSleepTimeMS < 0 || sleepTimeMS < minSleepTimeMS {
+			minSleepTimeMS = sleepTimeMS
+		}
+	}
+
+	return candidates[0].apiKey, minSleepTimeMS
+}
+
+// peek -- returns 0 if at least one key of the pool can afford request of given weight, or the shortest wait among all keys.
+func (kp *keyPool) peek(weight int, highPriority bool) int64 {
+	kp.mutex.Lock()
+	defer kp.mutex.Unlock()
+
+	minSleepTimeMS := int64(-1)
+
+	for _, key := range kp.keys {
+		sleepTimeMS := key.weightController.peek(weight, highPriority)
+		if sleepTimeMS == 0 {
+			return 0
+		}
+
+		if minSleepTimeMS < 0 || sleepTimeMS < minSleepTimeMS {
+			minSleepTimeMS = sleepTimeMS
+		}
+	}
+
+	return minSleepTimeMS
+}
@@ -0,0 +1,231 @@
+package bncclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// TimeRange -- closed time range [StartMS, EndMS], in milliseconds.
+type TimeRange struct {
+	StartMS int64 `json:"start"`
+	EndMS   int64 `json:"end"`
+}
+
+// KlineCache -- storage of historical (closed) klines, consulted by GetKlinesRange before fetching klines from Binance.
+// Cache remembers not only klines, but also time ranges it fully covers: there are periods without klines at all
+// (for example, when trading was suspended), and they shouldn't be requested again and again.
+type KlineCache interface {
+	// Get returns cached klines with OpenTime within [startTimeMS, endTimeMS] and sub-ranges of [startTimeMS, endTimeMS]
+	// which are fully covered by cache (klines of other sub-ranges will be fetched from Binance).
+	Get(symbol string, interval KlineInterval, startTimeMS int64, endTimeMS int64) ([]Kline, []TimeRange, error)
+	// Put stores ALL klines with OpenTime within [startTimeMS, endTimeMS]. Only closed klines are ever put to the cache.
+	Put(symbol string, interval KlineInterval, startTimeMS int64, endTimeMS int64, klines []Kline) error
+}
+
+// SetKlineCache - sets cache of historical klines used by GetKlinesRange: only ranges missing in the cache are requested
+// from Binance, and fetched closed klines are added to the cache. The current (open) kline is never cached.
+// nil disables caching. See NewFileKlineCache for simple file-backed implementation.
+func (bc *BinanceClient) SetKlineCache(cache KlineCache) {
+	bc.klineCache = cache
+}
+
+// FileKlineCache -- file-backed KlineCache: one JSON file per symbol and interval in the given directory.
+// Safe for concurrent use within one process.
+type FileKlineCache struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// fileKlineCacheContent -- format of cache file.
+type fileKlineCacheContent struct {
+	Ranges []TimeRange `json:"ranges"`
+	Klines []Kline     `json:"klines"` // Sorted by OpenTime
+}
+
+// NewFileKlineCache - creates file-backed kline cache in dir (directory is created if it doesn't exist).
+func NewFileKlineCache(dir string) (*FileKlineCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create kline cache directory: %w", err)
+	}
+
+	return &FileKlineCache{dir: dir}, nil
+}
+
+func (c *FileKlineCache) Get(symbol string, interval KlineInterval, startTimeMS int64, endTimeMS int64) ([]Kline, []TimeRange, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	content, err := c.load(symbol, interval)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	klines := make([]Kline, 0)
+	for _, kline := range content.Klines {
+		if kline.OpenTime >= startTimeMS && kline.OpenTime <= endTimeMS {
+			klines = append(klines, kline)
+		}
+	}
+
+	covered := make([]TimeRange, 0)
+	for _, timeRange := range content.Ranges {
+		if timeRange.EndMS < startTimeMS || timeRange.StartMS > endTimeMS {
+			continue
+		}
+		covered = append(covered, TimeRange{StartMS: maxInt64(timeRange.StartMS, startTimeMS), EndMS: minInt64(timeRange.EndMS, endTimeMS)})
+	}
+
+	return klines, covered, nil
+}
+
+func (c *FileKlineCache) Put(symbol string, interval KlineInterval, startTimeMS int64, endTimeMS int64, klines []Kline) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	content, err := c.load(symbol, interval)
+	if err != nil {
+		return err
+	}
+
+	klinesByOpenTime := make(map[int64]Kline, len(content.Klines)+len(klines))
+	for _, kline := range content.Klines {
+		klinesByOpenTime[kline.OpenTime] = kline
+	}
+	for _, kline := range klines {
+		klinesByOpenTime[kline.OpenTime] = kline
+	}
+
+	content.Klines = make([]Kline, 0, len(klinesByOpenTime))
+	for _, kline := range klinesByOpenTime {
+		content.Klines = append(content.Klines, kline)
+	}
+	sort.Slice(content.Klines, func(i, j int) bool {
+		return content.Klines[i].OpenTime < content.Klines[j].OpenTime
+	})
+
+	content.Ranges = mergeTimeRanges(append(content.Ranges, TimeRange{StartMS: startTimeMS, EndMS: endTimeMS}))
+
+	return c.save(symbol, interval, content)
+}
+
+// fileName -- name of cache file. Month interval "1M" is renamed, because "1m" and "1M" would be the same file
+// on case-insensitive file systems.
+func (c *FileKlineCache) fileName(symbol string, interval KlineInterval) string {
+	intervalName := interval.String()
+	if interval == Interval1M {
+		intervalName = "1month"
+	}
+
+	return filepath.Join(c.dir, strings.ToUpper(symbol)+"_"+intervalName+".json")
+}
+
+func (c *FileKlineCache) load(symbol string, interval KlineInterval) (fileKlineCacheContent, error) {
+	var content fileKlineCacheContent
+
+	data, err := os.ReadFile(c.fileName(symbol, interval))
+
+	if errors.Is(err, os.ErrNotExist) {
+		return content, nil
+	}
+
+	if err != nil {
+		return content, fmt.Errorf("failed to read kline cache: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &content); err != nil {
+		return content, fmt.Errorf("failed to parse kline cache: %w", err)
+	}
+
+	return content, nil
+}
+
+// save writes cache file atomically (via temporary file), so interrupted write never corrupts existing cache.
+func (c *FileKlineCache) save(symbol string, interval KlineInterval, content fileKlineCacheContent) error {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("failed to encode kline cache: %w", err)
+	}
+
+	fileName := c.fileName(symbol, interval)
+	tmpFileName := fileName + ".tmp"
+
+	if err := os.WriteFile(tmpFileName, data, 0644); err != nil {
+		return fmt.Errorf("failed to write kline cache: %w", err)
+	}
+
+	if err := os.Rename(tmpFileName, fileName); err != nil {
+		return fmt.Errorf("failed to write kline cache: %w", err)
+	}
+
+	return nil
+}
+
+// mergeTimeRanges sorts ranges and merges overlapping and adjacent ones.
+func mergeTimeRanges(ranges []TimeRange) []TimeRange {
+	if len(ranges) == 0 {
+		return ranges
+	}
+
+	sorted := make([]TimeRange, len(ranges))
+	copy(sorted, ranges)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].StartMS < sorted[j].StartMS
+	})
+
+	merged := []TimeRange{sorted[0]}
+	for _, timeRange := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if timeRange.StartMS <= last.EndMS+1 {
+			last.EndMS = maxInt64(last.EndMS, timeRange.EndMS)
+			continue
+		}
+		merged = append(merged, timeRange)
+	}
+
+	return merged
+}
+
+// missingTimeRanges returns sub-ranges of [startTimeMS, endTimeMS] not covered by any of covered ranges.
+func missingTimeRanges(startTimeMS int64, endTimeMS int64, covered []TimeRange) []TimeRange {
+	missing := make([]TimeRange, 0)
+	cursorMS := startTimeMS
+
+	for _, timeRange := range mergeTimeRanges(covered) {
+		if timeRange.EndMS < cursorMS {
+			continue
+		}
+		if timeRange.StartMS > endTimeMS {
+			break
+		}
+		if timeRange.StartMS > cursorMS {
+			missing = append(missing, TimeRange{StartMS: cursorMS, EndMS: timeRange.StartMS - 1})
+		}
+		cursorMS = timeRange.EndMS + 1
+	}
+
+	if cursorMS <= endTimeMS {
+		missing = append(missing, TimeRange{StartMS: cursorMS, EndMS: endTimeMS})
+	}
+
+	return missing
+}
+
+func minInt64(a int64, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt64(a int64, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
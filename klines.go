@@ -0,0 +1,399 @@
+package bncclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const klinesMaxLimit = 1000 // Binance returns maximum 1000 candles per request
+
+// KlineInterval -- interval of kline/candlestick, use predefined constants or ParseKlineInterval for user input.
+type KlineInterval string
+
+const (
+	Interval1s  KlineInterval = "1s"
+	Interval1m  KlineInterval = "1m"
+	Interval3m  KlineInterval = "3m"
+	Interval5m  KlineInterval = "5m"
+	Interval15m KlineInterval = "15m"
+	Interval30m KlineInterval = "30m"
+	Interval1h  KlineInterval = "1h"
+	Interval2h  KlineInterval = "2h"
+	Interval4h  KlineInterval = "4h"
+	Interval6h  KlineInterval = "6h"
+	Interval8h  KlineInterval = "8h"
+	Interval12h KlineInterval = "12h"
+	Interval1d  KlineInterval = "1d"
+	Interval3d  KlineInterval = "3d"
+	Interval1w  KlineInterval = "1w"
+	Interval1M  KlineInterval = "1M"
+)
+
+// klineIntervalDurations -- approximate duration of every supported kline interval.
+// Month is counted as 31 days, it's OK because we use it only to split time range into chunks.
+var klineIntervalDurations = map[KlineInterval]time.Duration{
+	Interval1s:  time.Second,
+	Interval1m:  time.Minute,
+	Interval3m:  3 * time.Minute,
+	Interval5m:  5 * time.Minute,
+	Interval15m: 15 * time.Minute,
+	Interval30m: 30 * time.Minute,
+	Interval1h:  time.Hour,
+	Interval2h:  2 * time.Hour,
+	Interval4h:  4 * time.Hour,
+	Interval6h:  6 * time.Hour,
+	Interval8h:  8 * time.Hour,
+	Interval12h: 12 * time.Hour,
+	Interval1d:  24 * time.Hour,
+	Interval3d:  3 * 24 * time.Hour,
+	Interval1w:  7 * 24 * time.Hour,
+	Interval1M:  31 * 24 * time.Hour,
+}
+
+// ParseKlineInterval converts user input (like "15m") to KlineInterval. Returns error if interval is not supported by Binance.
+// Note: interval is case-sensitive, "1m" is one minute while "1M" is one month.
+func ParseKlineInterval(interval string) (KlineInterval, error) {
+	klineInterval := KlineInterval(interval)
+
+	if !klineInterval.IsValid() {
+		return "", errors.New(fmt.Sprintf("Not allowed kline interval: %s", interval))
+	}
+
+	return klineInterval, nil
+}
+
+// IsValid checks if interval is one of intervals supported by Binance.
+func (ki KlineInterval) IsValid() bool {
+	_, exists := klineIntervalDurations[ki]
+	return exists
+}
+
+// String implements fmt.Stringer.
+func (ki KlineInterval) String() string {
+	return string(ki)
+}
+
+type Kline struct {
+	OpenTime                 int64
+	Open                     float64
+	High                     float64
+	Low                      float64
+	Close                    float64
+	Volume                   float64
+	CloseTime                int64
+	QuoteAssetVolume         float64
+	NumberOfTrades           int64
+	TakerBuyBaseAssetVolume  float64
+	TakerBuyQuoteAssetVolume float64
+}
+
+type KlinesList []Kline
+
+const klineMinElements = 11 // Elements up to TakerBuyQuoteAssetVolume, the trailing "ignore" element is optional
+
+// UnmarshalJSON parses kline from Binance format, which is array of mixed values (numbers and strings):
+// [openTime, "open", "high", "low", "close", "volume", closeTime, "quoteAssetVolume", numberOfTrades, "takerBuyBaseAssetVolume", "takerBuyQuoteAssetVolume", "ignore"]
+// The trailing "ignore" element is not used, so arrays without it (11 elements) are accepted too, as well as arrays with
+// extra trailing elements.
+func (k *Kline) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	if len(fields) < klineMinElements {
+		return fmt.Errorf("unexpected kline format: %d elements received, at least %d expected", len(fields), klineMinElements)
+	}
+
+	intTargets := map[int]*int64{0: &k.OpenTime, 6: &k.CloseTime, 8: &k.NumberOfTrades}
+	floatTargets := map[int]*float64{
+		1:  &k.Open,
+		2:  &k.High,
+		3:  &k.Low,
+		4:  &k.Close,
+		5:  &k.Volume,
+		7:  &k.QuoteAssetVolume,
+		9:  &k.TakerBuyBaseAssetVolume,
+		10: &k.TakerBuyQuoteAssetVolume,
+	}
+
+	for i, target := range intTargets {
+		if err := json.Unmarshal(fields[i], target); err != nil {
+			return fmt.Errorf("unexpected kline format: element %d: %s", i, err.Error())
+		}
+	}
+
+	for i, target := range floatTargets {
+		var valueStr string
+		if err := json.Unmarshal(fields[i], &valueStr); err != nil {
+			return fmt.Errorf("unexpected kline format: element %d: %s", i, err.Error())
+		}
+
+		value, err := strconv.ParseFloat(valueStr, 64)
+		if err != nil {
+			return fmt.Errorf("unexpected kline format: element %d: %s", i, err.Error())
+		}
+		*target = value
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes kline in Binance format (the same UnmarshalJSON accepts), with "0" as the trailing "ignore" element.
+func (k Kline) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{
+		k.OpenTime,
+		formatFloat(k.Open),
+		formatFloat(k.High),
+		formatFloat(k.Low),
+		formatFloat(k.Close),
+		formatFloat(k.Volume),
+		k.CloseTime,
+		formatFloat(k.QuoteAssetVolume),
+		k.NumberOfTrades,
+		formatFloat(k.TakerBuyBaseAssetVolume),
+		formatFloat(k.TakerBuyQuoteAssetVolume),
+		"0",
+	})
+}
+
+// GetKlines - Kline/candlestick bars for a symbol. Klines are uniquely identified by their open time.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#klinecandlestick-data
+// Parameters startTimeMS, endTimeMS and limit are optional, set them to -1 if you don't want to specify them.
+func (bc *BinanceClient) GetKlines(symbol string, interval KlineInterval, startTimeMS int64, endTimeMS int64, limit int) (KlinesList, Warning, error) {
+	if !interval.IsValid() {
+		return nil, nil, errors.New(fmt.Sprintf("Not allowed kline interval: %s", interval))
+	}
+
+	var klines KlinesList
+
+	warning, err := bc.newRequest(http.MethodGet).
+		AddString("symbol", symbol).
+		AddString("interval", interval.String()).
+		AddInt64("startTime", startTimeMS, true).
+		AddInt64("endTime", endTimeMS, true).
+		AddInt("limit", limit, true).
+		Do("/api/v3/klines", 2, &klines)
+
+	if err != nil || warning != nil {
+		return nil, warning, err
+	}
+
+	return klines, nil, nil
+}
+
+// GetClosedKlines - the same as GetKlines, but the last kline is dropped if it's still open (its CloseTime is not
+// in the past by server time estimate, see EstimatedServerTimeMS), so indicators are computed on finalized klines only.
+// Call SyncTime first, if local clock may be inaccurate.
+func (bc *BinanceClient) GetClosedKlines(symbol string, interval KlineInterval, startTimeMS int64, endTimeMS int64, limit int) (KlinesList, Warning, error) {
+	klines, warning, err := bc.GetKlines(symbol, interval, startTimeMS, endTimeMS, limit)
+
+	if err != nil || warning != nil {
+		return nil, warning, err
+	}
+
+	if len(klines) > 0 && bc.timestampToMS(klines[len(klines)-1].CloseTime) >= bc.EstimatedServerTimeMS() {
+		klines = klines[:len(klines)-1]
+	}
+
+	return klines, nil, nil
+}
+
+// GetKlinesRange - gets all klines between startTimeMS and endTimeMS (both inclusive), splitting the range into
+// requests of maximum 1000 candles each. When weight controller returns a Warning, it sleeps recommended time and continues.
+// Returned klines are deduplicated by OpenTime and sorted by OpenTime.
+// Sleeping (and in-flight request) can be interrupted by cancelling ctx, in this case ctx.Err() is returned.
+// If kline cache is set (see SetKlineCache), only ranges missing in the cache are requested, and fetched closed klines
+// are added to the cache. Cache is not used when time unit is not milliseconds (see SetTimeUnit).
+func (bc *BinanceClient) GetKlinesRange(ctx context.Context, symbol string, interval KlineInterval, startTimeMS int64, endTimeMS int64) ([]Kline, error) {
+	klines, _, _, err := bc.getKlinesRange(ctx, symbol, interval, startTimeMS, endTimeMS, false)
+	return klines, err
+}
+
+// KlinesBatchResult -- result of GetKlinesRangeBatch (see BatchResult).
+// Resume is startTimeMS to continue from, or -1 when the whole range was fetched.
+type KlinesBatchResult struct {
+	Data    []Kline
+	Resume  int64
+	Warning Warning
+}
+
+func (r KlinesBatchResult) GetWarning() Warning {
+	return r.Warning
+}
+
+func (r KlinesBatchResult) IsComplete() bool {
+	return r.Resume < 0
+}
+
+// GetKlinesRangeBatch - the same as GetKlinesRange, but instead of sleeping when weight controller returns a Warning,
+// it stops and returns klines fetched so far together with the Warning and the cursor to resume from:
+//
+//	result, err := bc.GetKlinesRangeBatch(ctx, symbol, interval, startTimeMS, endTimeMS)
+//	// ... if !result.IsComplete(): sleep result.Warning.GetRetryAfterTimeMS() and call again with result.Resume as startTimeMS
+func (bc *BinanceClient) GetKlinesRangeBatch(ctx context.Context, symbol string, interval KlineInterval, startTimeMS int64, endTimeMS int64) (KlinesBatchResult, error) {
+	klines, warning, resumeMS, err := bc.getKlinesRange(ctx, symbol, interval, startTimeMS, endTimeMS, true)
+
+	if err != nil {
+		return KlinesBatchResult{}, err
+	}
+
+	return KlinesBatchResult{Data: klines, Resume: resumeMS, Warning: warning}, nil
+}
+
+// getKlinesRange -- implementation of GetKlinesRange and GetKlinesRangeBatch. If stopOnWarning is false, it sleeps on Warning
+// and continues, otherwise it returns klines fetched so far, the Warning and startTimeMS to resume from (-1 when complete).
+func (bc *BinanceClient) getKlinesRange(ctx context.Context, symbol string, interval KlineInterval, startTimeMS int64, endTimeMS int64, stopOnWarning bool) ([]Kline, Warning, int64, error) {
+	intervalDuration, exists := klineIntervalDurations[interval]
+	if !exists {
+		return nil, nil, -1, errors.New(fmt.Sprintf("Not allowed kline interval: %s", interval))
+	}
+
+	if startTimeMS > endTimeMS {
+		return nil, nil, -1, errors.New("startTimeMS should not be greater than endTimeMS")
+	}
+
+	klinesByOpenTime := make(map[int64]Kline)
+	missingRanges := []TimeRange{{StartMS: startTimeMS, EndMS: endTimeMS}}
+	useCache := bc.klineCache != nil && (bc.timeUnit == "" || bc.timeUnit == TimeUnitMillisecond)
+
+	if useCache {
+		cachedKlines, coveredRanges, err := bc.klineCache.Get(symbol, interval, startTimeMS, endTimeMS)
+		if err != nil {
+			return nil, nil, -1, err
+		}
+
+		for _, kline := range cachedKlines {
+			klinesByOpenTime[kline.OpenTime] = kline
+		}
+
+		missingRanges = missingTimeRanges(startTimeMS, endTimeMS, coveredRanges)
+	}
+
+	for _, missingRange := range missingRanges {
+		fetchedKlines, warning, resumeMS, err := bc.fetchKlinesRange(ctx, symbol, interval, intervalDuration, missingRange.StartMS, missingRange.EndMS, stopOnWarning)
+		if err != nil {
+			return nil, nil, -1, err
+		}
+
+		for _, kline := range fetchedKlines {
+			klinesByOpenTime[kline.OpenTime] = kline
+		}
+
+		fetchedRange := missingRange
+		if warning != nil {
+			fetchedRange.EndMS = resumeMS - 1
+		}
+
+		if useCache && fetchedRange.EndMS >= fetchedRange.StartMS {
+			if err := bc.putClosedKlinesToCache(symbol, interval, intervalDuration, fetchedRange, fetchedKlines); err != nil {
+				return nil, nil, -1, err
+			}
+		}
+
+		if warning != nil {
+			return sortKlinesByOpenTime(klinesByOpenTime), warning, resumeMS, nil
+		}
+	}
+
+	return sortKlinesByOpenTime(klinesByOpenTime), nil, -1, nil
+}
+
+// fetchKlinesRange requests all klines between startTimeMS and endTimeMS (both inclusive) from Binance, chunk by chunk.
+// Returned klines are not deduplicated. If stopOnWarning is true, on Warning it returns klines fetched so far,
+// the Warning and startTimeMS to resume from.
+func (bc *BinanceClient) fetchKlinesRange(ctx context.Context, symbol string, interval KlineInterval, intervalDuration time.Duration, startTimeMS int64, endTimeMS int64, stopOnWarning bool) ([]Kline, Warning, int64, error) {
+	chunkDurationMS := klinesMaxLimit * intervalDuration.Milliseconds()
+	result := make([]Kline, 0)
+	cursorMS := startTimeMS
+
+	for cursorMS <= endTimeMS {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, -1, err
+		}
+
+		chunkEndMS := cursorMS + chunkDurationMS - 1
+		if chunkEndMS > endTimeMS {
+			chunkEndMS = endTimeMS
+		}
+
+		klines, warning, err := bc.WithContext(ctx).GetKlines(symbol, interval, cursorMS, chunkEndMS, klinesMaxLimit)
+		warning, err = splitWarning(warning, err)
+
+		if err != nil {
+			return nil, nil, -1, err
+		}
+
+		if warning != nil {
+			if stopOnWarning {
+				return result, warning, cursorMS, nil
+			}
+			if err := sleepWithContext(ctx, warning.GetRetryAfterTimeMS()); err != nil {
+				return nil, nil, -1, err
+			}
+			continue // Repeat the same chunk after sleep
+		}
+
+		result = append(result, klines...)
+
+		if len(klines) == klinesMaxLimit {
+			cursorMS = bc.timestampToMS(klines[len(klines)-1].OpenTime) + 1 // Chunk may be not exhausted yet, continue right after the last candle
+		} else {
+			cursorMS = chunkEndMS + 1
+		}
+	}
+
+	return result, nil, -1, nil
+}
+
+// putClosedKlinesToCache puts to the cache part of fetched range, which can't contain open kline anymore.
+func (bc *BinanceClient) putClosedKlinesToCache(symbol string, interval KlineInterval, intervalDuration time.Duration, fetchedRange TimeRange, klines []Kline) error {
+	nowMS := currentTimestampMS()
+	closedEndMS := minInt64(fetchedRange.EndMS, nowMS-intervalDuration.Milliseconds()) // Klines opened later may be still open
+
+	if closedEndMS < fetchedRange.StartMS {
+		return nil
+	}
+
+	closedKlines := make([]Kline, 0, len(klines))
+	for _, kline := range klines {
+		if kline.OpenTime <= closedEndMS && kline.CloseTime < nowMS {
+			closedKlines = append(closedKlines, kline)
+		}
+	}
+
+	return bc.klineCache.Put(symbol, interval, fetchedRange.StartMS, closedEndMS, closedKlines)
+}
+
+func sortKlinesByOpenTime(klinesByOpenTime map[int64]Kline) []Kline {
+	result := make([]Kline, 0, len(klinesByOpenTime))
+	for _, kline := range klinesByOpenTime {
+		result = append(result, kline)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].OpenTime < result[j].OpenTime
+	})
+
+	return result
+}
+
+// sleepWithContext sleeps given amount of milliseconds, or less, if ctx is cancelled earlier (then ctx.Err() is returned).
+func sleepWithContext(ctx context.Context, sleepTimeMS int64) error {
+	timer := time.NewTimer(time.Duration(sleepTimeMS) * time.Millisecond)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
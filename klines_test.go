@@ -0,0 +1,44 @@
+package bncclient
+
+import (
+	"testing"
+)
+
+func TestKlineOptionalIgnoreField(t *testing.T) {
+	expected := Kline{
+		OpenTime:                 1499040000000,
+		Open:                     0.0163479,
+		High:                     0.8,
+		Low:                      0.015758,
+		Close:                    0.015771,
+		Volume:                   148976.11427815,
+		CloseTime:                1499644799999,
+		QuoteAssetVolume:         2434.19055334,
+		NumberOfTrades:           308,
+		TakerBuyBaseAssetVolume:  1756.87402397,
+		TakerBuyQuoteAssetVolume: 28.46694368,
+	}
+
+	const elevenFields = `1499040000000,"0.01634790","0.80000000","0.01575800","0.01577100","148976.11427815",1499644799999,"2434.19055334",308,"1756.87402397","28.46694368"`
+
+	bodies := map[string]string{
+		"11 elements": `[[` + elevenFields + `]]`,
+		"12 elements": `[[` + elevenFields + `,"17928899.62484339"]]`,
+	}
+
+	for name, body := range bodies {
+		var klines KlinesList
+		if err := NewPublicClient().tryParseArrayResponse([]byte(body), &klines); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		if len(klines) != 1 || klines[0] != expected {
+			t.Errorf("%s: expected %+v, got %+v", name, expected, klines)
+		}
+	}
+
+	var klines KlinesList
+	if err := NewPublicClient().tryParseArrayResponse([]byte(`[[1499040000000,"0.01634790","0.80000000"]]`), &klines); err == nil {
+		t.Error("expected error for kline with less than 11 elements")
+	}
+}
@@ -0,0 +1,93 @@
+package bncclient
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// clientLifecycle tracks background goroutines and stream connections of the client, so Close() can stop all of them.
+type clientLifecycle struct {
+	closed    int32         // Set to 1 (atomically) when Close() has finished stopping streams
+	done      chan struct{} // Closed at the beginning of Close()
+	closeOnce sync.Once
+	streams   map[io.Closer]struct{} // Open stream connections (user data streams, combined streams)
+	mutex     sync.Mutex
+}
+
+func newClientLifecycle() *clientLifecycle {
+	return &clientLifecycle{
+		done:    make(chan struct{}),
+		streams: make(map[io.Closer]struct{}),
+	}
+}
+
+// Close - releases resources of the client: stops background goroutines (polling streams, listen key keepalives),
+// closes stream connections (and listen keys of user data streams) and idle HTTP connections.
+// The client is unusable after Close: every request returns ErrClientClosed. Safe to call several times,
+// only the first call does the job. Returned error is the first error occurred while closing streams.
+func (bc *BinanceClient) Close() error {
+	var closeErr error
+
+	bc.lifecycle.closeOnce.Do(func() {
+		close(bc.lifecycle.done)
+
+		bc.lifecycle.mutex.Lock()
+		streams := make([]io.Closer, 0, len(bc.lifecycle.streams))
+		for stream := range bc.lifecycle.streams {
+			streams = append(streams, stream)
+		}
+		bc.lifecycle.mutex.Unlock()
+
+		// Streams are closed before the client is marked as closed, because closing of listen key is a request itself:
+		for _, stream := range streams {
+			if err := stream.Close(); err != nil && closeErr == nil {
+				closeErr = err
+			}
+		}
+
+		atomic.StoreInt32(&bc.lifecycle.closed, 1)
+
+		bc.defaultHTTPClient.CloseIdleConnections()
+		if idleCloser, ok := bc.httpClient.(interface{ CloseIdleConnections() }); ok {
+			idleCloser.CloseIdleConnections()
+		}
+	})
+
+	return closeErr
+}
+
+func (l *clientLifecycle) isClosed() bool {
+	return atomic.LoadInt32(&l.closed) == 1
+}
+
+// bindContext returns context which is cancelled when parent is cancelled or when the client is closed.
+// Background goroutines should use it instead of the context received from caller.
+func (l *clientLifecycle) bindContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	go func() {
+		select {
+		case <-l.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+func (l *clientLifecycle) registerStream(stream io.Closer) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.streams[stream] = struct{}{}
+}
+
+func (l *clientLifecycle) unregisterStream(stream io.Closer) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	delete(l.streams, stream)
+}
@@ -0,0 +1,573 @@
+package bncclient
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const managedOrderBookSnapshotLimit = 1000       // Depth of snapshot the local book is initialized from
+const managedOrderBookDefaultVerifyLevels = 20   // How many top levels Verify compares by default
+const managedOrderBookVerifyWaitMS = 3000        // How long Verify waits for the local book to catch up with snapshot
+const managedOrderBookTombstoneTTL = time.Minute // How long removed levels are remembered (for Verify)
+const managedOrderBookPruneEveryEvents = 100     // How often removed levels are pruned
+const managedOrderBookVerifyPollIntervalMS = 50  // How often Verify checks if the local book caught up
+const managedOrderBookSyncBackoffMinMS = 250     // Delay before the next snapshot request after failed synchronization
+const managedOrderBookSyncBackoffMaxMS = 10000   // Delay is doubled after every failed attempt up to this value
+
+// depthUpdateEvent -- event of diff. depth stream.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/web-socket-streams.md#diff-depth-stream
+type depthUpdateEvent struct {
+	EventType     string          `json:"e"`
+	EventTime     int64           `json:"E"`
+	Symbol        string          `json:"s"`
+	FirstUpdateId int64           `json:"U"`
+	FinalUpdateId int64           `json:"u"`
+	Bids          [][]json.Number `json:"b"`
+	Asks          [][]json.Number `json:"a"`
+}
+
+// bookLevel -- level of managed order book. Removed levels are kept for a while with zero quantity, so Verify can
+// tell levels removed after the snapshot from levels which are missing by mistake.
+type bookLevel struct {
+	qty       float64
+	updateId  int64 // Update id of the event which changed the level last time
+	changedAt time.Time
+}
+
+// ManagedOrderBook -- local order book of a symbol, maintained from diff. depth stream according to Binance algorithm:
+// https://github.com/binance/binance-spot-api-docs/blob/master/web-socket-streams.md#how-to-manage-a-local-order-book-correctly
+// When a gap in update ids is detected, the book is re-synchronized from a new snapshot automatically. Snapshot is
+// requested off the read loop, one request at a time, and failed attempts are retried with growing delay (250ms up to 10s).
+// Errors (connection failures, failed snapshot requests, divergence found by periodic verification) are delivered
+// via Errors() channel.
+type ManagedOrderBook struct {
+	client           *BinanceClient
+	symbol           string
+	conn             *websocket.Conn
+	bids             map[float64]bookLevel
+	asks             map[float64]bookLevel
+	lastUpdateId     int64
+	synced           bool
+	diverged         bool
+	buffer           []depthUpdateEvent // Events received while the book is not synchronized
+	syncing          bool               // Snapshot request is in flight (only one at a time)
+	syncFailures     int                // Failed synchronization attempts in a row
+	nextSyncAt       time.Time          // No new snapshot requests before this time (backoff after failures)
+	eventsCount      int
+	verifyLevels     int
+	autoResync       bool
+	stopVerification chan struct{}
+	mutex            sync.Mutex
+	errors           chan error
+	errorsMutex      sync.Mutex
+	errorsClosed     bool
+	done             chan struct{} // Closed by Close()
+	closeOnce        sync.Once
+	closeErr         error
+}
+
+// StartManagedOrderBook - connects to diff. depth stream of the symbol (100ms updates) and maintains local order book.
+// The book becomes available (see IsSynced) after the first snapshot is applied. Call Close() when it's not needed anymore.
+func (bc *BinanceClient) StartManagedOrderBook(symbol string) (*ManagedOrderBook, error) {
+	if bc.lifecycle.isClosed() {
+		return nil, ErrClientClosed
+	}
+
+	conn, _, err := bc.newWebsocketDialer().Dial(bc.streamBaseURL+"/ws/"+normalizeStreamNames([]string{symbol + "@depth@100ms"})[0], nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	book := &ManagedOrderBook{
+		client:       bc,
+		symbol:       symbol,
+		conn:         conn,
+		bids:         make(map[float64]bookLevel),
+		asks:         make(map[float64]bookLevel),
+		verifyLevels: managedOrderBookDefaultVerifyLevels,
+		errors:       make(chan error, 1),
+		done:         make(chan struct{}),
+	}
+
+	bc.lifecycle.registerStream(book)
+
+	go book.readLoop()
+
+	select {
+	case <-bc.lifecycle.done: // Client was closed while the book was starting, so Close() could miss it
+		_ = book.Close()
+		return nil, ErrClientClosed
+	default:
+	}
+
+	return book, nil
+}
+
+// IsSynced returns true if the local book is synchronized with Binance.
+func (m *ManagedOrderBook) IsSynced() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.synced
+}
+
+// IsDiverged returns true if the last verification found divergence (and the book was not re-synchronized after that).
+func (m *ManagedOrderBook) IsDiverged() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.diverged
+}
+
+// Snapshot returns copy of top levels of the local book (bids descending, asks ascending), levels <= 0 means all levels.
+// Second value is false if the book is not synchronized (then returned book should not be used).
+// Updates are applied exactly as Binance sends them, so synchronized book still can be crossed or have an empty side
+// for a moment (between updates of bids and asks which are sent separately). Such state is not corrected, it goes away
+// with subsequent updates: check IsCrossed and IsEmpty of returned book and skip it, if pricing decisions depend on it.
+func (m *ManagedOrderBook) Snapshot(levels int) (OrderBook, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return OrderBook{
+		LastUpdateId: m.lastUpdateId,
+		Bids:         topLevels(m.bids, levels, true),
+		Asks:         topLevels(m.asks, levels, false),
+	}, m.synced
+}
+
+// SetVerification - configures self-check of the local book: every interval a fresh snapshot is requested with
+// GetOrderBook and its top levels are compared with the local book (see Verify). Divergence is reported
+// to Errors() channel as ErrOrderBookDiverged, and if autoResync is true, the book is re-synchronized.
+// Zero interval disables periodic verification (Verify can still be called manually). levels <= 0 means default (20).
+func (m *ManagedOrderBook) SetVerification(interval time.Duration, levels int, autoResync bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if levels <= 0 {
+		levels = managedOrderBookDefaultVerifyLevels
+	}
+
+	m.verifyLevels = levels
+	m.autoResync = autoResync
+
+	if m.stopVerification != nil {
+		close(m.stopVerification)
+		m.stopVerification = nil
+	}
+
+	if interval > 0 {
+		m.stopVerification = make(chan struct{})
+		go m.verificationLoop(interval, m.stopVerification)
+	}
+}
+
+// Verify - requests a fresh snapshot and compares its top levels with the local book. Levels changed by updates
+// newer than the snapshot are skipped. Returns false if the book diverged (if auto-resync is on, re-synchronization
+// is started). Error is returned if the snapshot can't be requested or the local book is not synchronized.
+// ErrClientClosed is returned if the book is closed while it catches up with the snapshot.
+func (m *ManagedOrderBook) Verify() (bool, error) {
+	m.mutex.Lock()
+	levels := m.verifyLevels
+	m.mutex.Unlock()
+
+	snapshot, warning, err := m.client.GetOrderBook(m.symbol, verifySnapshotLimit(levels))
+	warning, err = splitWarning(warning, err)
+
+	if err != nil {
+		return false, err
+	}
+
+	if warning != nil {
+		return false, warning
+	}
+
+	// Local book may be a bit behind the snapshot, let it catch up:
+	for waitedMS := 0; ; waitedMS += managedOrderBookVerifyPollIntervalMS {
+		m.mutex.Lock()
+		caughtUp := m.synced && m.lastUpdateId >= snapshot.LastUpdateId
+		m.mutex.Unlock()
+
+		if caughtUp {
+			break
+		}
+
+		if waitedMS >= managedOrderBookVerifyWaitMS {
+			return false, ErrOrderBookNotSynced
+		}
+
+		select {
+		case <-m.done: // Book was closed while waiting, it will never catch up
+			return false, ErrClientClosed
+		case <-time.After(managedOrderBookVerifyPollIntervalMS * time.Millisecond):
+		}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	matches := levelsMatch(m.bids, snapshot.SortedBids(), levels, snapshot.LastUpdateId, true) &&
+		levelsMatch(m.asks, snapshot.SortedAsks(), levels, snapshot.LastUpdateId, false)
+
+	if !matches {
+		m.diverged = true
+		if m.autoResync {
+			m.synced = false
+			m.buffer = nil
+		}
+	}
+
+	return matches, nil
+}
+
+// Close closes websocket connection and stops verification. Safe to call several times.
+// Managed order books are closed automatically by BinanceClient.Close() too.
+func (m *ManagedOrderBook) Close() error {
+	m.closeOnce.Do(func() {
+		defer m.client.lifecycle.unregisterStream(m)
+		close(m.done)
+
+		m.mutex.Lock()
+		if m.stopVerification != nil {
+			close(m.stopVerification)
+			m.stopVerification = nil
+		}
+		m.mutex.Unlock()
+
+		_ = m.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		m.closeErr = m.conn.Close()
+	})
+
+	return m.closeErr
+}
+
+// Errors returns channel of errors. Channel is closed when the book stops.
+func (m *ManagedOrderBook) Errors() <-chan error {
+	return m.errors
+}
+
+func (m *ManagedOrderBook) readLoop() {
+	defer m.closeErrors()
+
+	for {
+		_, message, err := m.conn.ReadMessage()
+
+		if err != nil {
+			select {
+			case <-m.done: // Connection was closed by Close(), it's not an error
+			default:
+				m.reportError(err)
+			}
+			return
+		}
+
+		var event depthUpdateEvent
+		if err := json.Unmarshal(message, &event); err != nil {
+			m.reportError(err)
+			continue
+		}
+
+		if event.EventType != "depthUpdate" {
+			continue
+		}
+
+		if !m.handleEvent(event) {
+			m.startSync()
+		}
+	}
+}
+
+// handleEvent applies event to synchronized book. Returns false if the book is not synchronized (then event is buffered).
+func (m *ManagedOrderBook) handleEvent(event depthUpdateEvent) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.synced {
+		if event.FinalUpdateId <= m.lastUpdateId {
+			return true // Outdated event
+		}
+
+		if event.FirstUpdateId == m.lastUpdateId+1 {
+			m.apply(event)
+			return true
+		}
+
+		m.synced = false // Some events were missed, re-synchronization is needed
+		m.buffer = nil
+	}
+
+	m.buffer = append(m.buffer, event)
+
+	return false
+}
+
+// startSync starts synchronization in background, so the read loop keeps buffering events while the snapshot
+// is requested. Nothing is started if synchronization is already in flight, or if backoff after failed attempt is not over.
+func (m *ManagedOrderBook) startSync() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.syncing || time.Now().Before(m.nextSyncAt) {
+		return
+	}
+
+	m.syncing = true
+
+	go m.sync()
+}
+
+// sync requests snapshot and applies it together with buffered events. If snapshot is older than buffered events
+// (or the request fails), nothing is changed, and an event received after backoff delay triggers new attempt.
+func (m *ManagedOrderBook) sync() {
+	synced := false
+
+	defer func() {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		m.syncing = false
+
+		if synced {
+			m.syncFailures = 0
+			m.nextSyncAt = time.Time{}
+			return
+		}
+
+		m.nextSyncAt = time.Now().Add(syncBackoff(m.syncFailures))
+		m.syncFailures++
+	}()
+
+	snapshot, warning, err := m.client.GetOrderBook(m.symbol, managedOrderBookSnapshotLimit)
+	warning, err = splitWarning(warning, err)
+
+	if err != nil {
+		m.reportError(err)
+		return
+	}
+
+	if warning != nil {
+		m.reportError(warning)
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.synced {
+		synced = true
+		return
+	}
+
+	if len(m.buffer) == 0 || snapshot.LastUpdateId < m.buffer[0].FirstUpdateId {
+		return
+	}
+
+	m.bids = make(map[float64]bookLevel, len(snapshot.Bids))
+	m.asks = make(map[float64]bookLevel, len(snapshot.Asks))
+	now := time.Now()
+
+	for _, level := range snapshot.Bids {
+		m.bids[level.Price] = bookLevel{qty: level.Qty, updateId: snapshot.LastUpdateId, changedAt: now}
+	}
+
+	for _, level := range snapshot.Asks {
+		m.asks[level.Price] = bookLevel{qty: level.Qty, updateId: snapshot.LastUpdateId, changedAt: now}
+	}
+
+	m.lastUpdateId = snapshot.LastUpdateId
+
+	for _, event := range m.buffer {
+		if event.FinalUpdateId <= m.lastUpdateId {
+			continue
+		}
+
+		if event.FirstUpdateId > m.lastUpdateId+1 { // Events between snapshot and buffer are missing, start over
+			m.buffer = nil
+			return
+		}
+
+		m.apply(event)
+	}
+
+	m.buffer = nil
+	m.synced = true
+	m.diverged = false
+	synced = true
+}
+
+// syncBackoff -- delay before the next synchronization attempt after given number of failed attempts in a row.
+func syncBackoff(failures int) time.Duration {
+	delayMS := int64(managedOrderBookSyncBackoffMinMS)
+	for i := 0; i < failures && delayMS < managedOrderBookSyncBackoffMaxMS; i++ {
+		delayMS *= 2
+	}
+
+	if delayMS > managedOrderBookSyncBackoffMaxMS {
+		delayMS = managedOrderBookSyncBackoffMaxMS
+	}
+
+	return time.Duration(delayMS) * time.Millisecond
+}
+
+// apply applies event to the book. MUST be called with the mutex held.
+func (m *ManagedOrderBook) apply(event depthUpdateEvent) {
+	now := time.Now()
+
+	applySide := func(side map[float64]bookLevel, updates [][]json.Number) {
+		for _, update := range updates {
+			if len(update) < 2 {
+				continue
+			}
+
+			price, priceErr := update[0].Float64()
+			qty, qtyErr := update[1].Float64()
+
+			if priceErr != nil || qtyErr != nil {
+				continue
+			}
+
+			side[price] = bookLevel{qty: qty, updateId: event.FinalUpdateId, changedAt: now} // Zero qty marks removed level
+		}
+	}
+
+	applySide(m.bids, event.Bids)
+	applySide(m.asks, event.Asks)
+	m.lastUpdateId = event.FinalUpdateId
+
+	m.eventsCount++
+	if m.eventsCount%managedOrderBookPruneEveryEvents == 0 {
+		pruneRemovedLevels(m.bids, now)
+		pruneRemovedLevels(m.asks, now)
+	}
+}
+
+func (m *ManagedOrderBook) verificationLoop(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-m.done:
+			return
+		case <-ticker.C:
+			matches, err := m.Verify()
+			if err != nil {
+				m.reportError(err)
+			} else if !matches {
+				m.reportError(ErrOrderBookDiverged)
+			}
+		}
+	}
+}
+
+// reportError sends error to errors channel without blocking. If previous error was not read yet, the new one is dropped.
+func (m *ManagedOrderBook) reportError(err error) {
+	m.errorsMutex.Lock()
+	defer m.errorsMutex.Unlock()
+
+	if m.errorsClosed {
+		return
+	}
+
+	select {
+	case m.errors <- err:
+	default:
+	}
+}
+
+func (m *ManagedOrderBook) closeErrors() {
+	m.errorsMutex.Lock()
+	defer m.errorsMutex.Unlock()
+
+	m.errorsClosed = true
+	close(m.errors)
+}
+
+// topLevels returns existing (not removed) levels of the side sorted from the best price, levels <= 0 means all levels.
+func topLevels(side map[float64]bookLevel, levels int, descending bool) []PriceLevel {
+	result := make([]PriceLevel, 0, len(side))
+	for price, level := range side {
+		if level.qty > 0 {
+			result = append(result, PriceLevel{Price: price, Qty: level.qty})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if descending {
+			return result[i].Price > result[j].Price
+		}
+		return result[i].Price < result[j].Price
+	})
+
+	if levels > 0 && len(result) > levels {
+		result = result[:levels]
+	}
+
+	return result
+}
+
+// levelsMatch compares top levels of local side with snapshot side (sorted from the best price).
+// Levels changed after the snapshot (updateId > snapshotUpdateId) are skipped.
+func levelsMatch(side map[float64]bookLevel, snapshotLevels []PriceLevel, levels int, snapshotUpdateId int64, descending bool) bool {
+	if len(snapshotLevels) > levels {
+		snapshotLevels = snapshotLevels[:levels]
+	}
+
+	snapshotQty := make(map[float64]float64, len(snapshotLevels))
+	for _, level := range snapshotLevels {
+		snapshotQty[level.Price] = level.Qty
+
+		localLevel, exists := side[level.Price]
+		if exists && localLevel.updateId > snapshotUpdateId {
+			continue
+		}
+
+		if localLevel.qty != level.Qty {
+			return false
+		}
+	}
+
+	// Local levels within the price range of the snapshot must be present in the snapshot too:
+	for _, level := range topLevels(side, levels, descending) {
+		if side[level.Price].updateId > snapshotUpdateId {
+			continue
+		}
+
+		if len(snapshotLevels) == levels {
+			worstPrice := snapshotLevels[len(snapshotLevels)-1].Price
+			if (descending && level.Price < worstPrice) || (!descending && level.Price > worstPrice) {
+				continue
+			}
+		}
+
+		if snapshotQty[level.Price] != level.Qty {
+			return false
+		}
+	}
+
+	return true
+}
+
+// pruneRemovedLevels forgets levels removed long ago.
+func pruneRemovedLevels(side map[float64]bookLevel, now time.Time) {
+	for price, level := range side {
+		if level.qty == 0 && now.Sub(level.changedAt) > managedOrderBookTombstoneTTL {
+			delete(side, price)
+		}
+	}
+}
+
+// verifySnapshotLimit returns depth limit which covers given number of levels.
+func verifySnapshotLimit(levels int) int {
+	if levels > maxOrderBookLimit {
+		return maxOrderBookLimit
+	}
+
+	return levels
+}
@@ -0,0 +1,81 @@
+package bncclient
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestManagedOrderBookSyncSingleInFlightWithBackoff(t *testing.T) {
+	var requestsCount int32
+	releaseResponse := make(chan struct{})
+
+	bc := NewTestnetClient("test-api-key", "test-secret-key")
+	bc.SetHTTPClient(doerFunc(func(request *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requestsCount, 1)
+		<-releaseResponse
+		// Snapshot is older than buffered events, so synchronization fails:
+		return jsonResponse(request, `{"lastUpdateId":5,"bids":[],"asks":[]}`), nil
+	}))
+
+	book := &ManagedOrderBook{
+		client: bc,
+		symbol: "BTCUSDT",
+		bids:   make(map[float64]bookLevel),
+		asks:   make(map[float64]bookLevel),
+		errors: make(chan error, 1),
+		done:   make(chan struct{}),
+	}
+
+	for i := 0; i < 10; i++ { // Every event received while unsynced asks for synchronization
+		book.handleEvent(depthUpdateEvent{EventType: "depthUpdate", FirstUpdateId: int64(100 + i), FinalUpdateId: int64(100 + i)})
+		book.startSync()
+	}
+
+	close(releaseResponse)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		book.mutex.Lock()
+		syncing := book.syncing
+		book.mutex.Unlock()
+
+		if !syncing {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("synchronization didn't finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	book.startSync() // Within backoff delay after failed attempt
+
+	if count := atomic.LoadInt32(&requestsCount); count != 1 {
+		t.Fatalf("expected 1 snapshot request, got %d", count)
+	}
+
+	if book.IsSynced() {
+		t.Fatal("expected book not to be synced with outdated snapshot")
+	}
+}
+
+func TestSyncBackoff(t *testing.T) {
+	expected := []time.Duration{
+		250 * time.Millisecond,
+		500 * time.Millisecond,
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		10 * time.Second,
+		10 * time.Second,
+	}
+
+	for failures, delay := range expected {
+		if actual := syncBackoff(failures); actual != delay {
+			t.Errorf("failures %d: expected %v, got %v", failures, delay, actual)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package bncclient
+
+import (
+	"net/http"
+)
+
+// MarginAsset -- balance of an asset in cross margin account. Unlike spot balance, it includes borrowed amount,
+// accrued interest and net asset (free + locked - borrowed - interest).
+type MarginAsset struct {
+	Asset    string  `json:"asset"`
+	Borrowed float64 `json:"borrowed,string"`
+	Free     float64 `json:"free,string"`
+	Interest float64 `json:"interest,string"`
+	Locked   float64 `json:"locked,string"`
+	NetAsset float64 `json:"netAsset,string"`
+}
+
+// MarginAccount -- cross margin account details.
+type MarginAccount struct {
+	BorrowEnabled       bool          `json:"borrowEnabled"`
+	MarginLevel         float64       `json:"marginLevel,string"`
+	TotalAssetOfBtc     float64       `json:"totalAssetOfBtc,string"`
+	TotalLiabilityOfBtc float64       `json:"totalLiabilityOfBtc,string"`
+	TotalNetAssetOfBtc  float64       `json:"totalNetAssetOfBtc,string"`
+	TradeEnabled        bool          `json:"tradeEnabled"`
+	TransferEnabled     bool          `json:"transferEnabled"`
+	UserAssets          []MarginAsset `json:"userAssets"`
+}
+
+// GetAsset returns margin balance of the asset, second value is false if there is no such asset in the account.
+func (ma MarginAccount) GetAsset(asset string) (MarginAsset, bool) {
+	for _, marginAsset := range ma.UserAssets {
+		if marginAsset.Asset == asset {
+			return marginAsset, true
+		}
+	}
+
+	return MarginAsset{}, false
+}
+
+// GetMarginAccount - cross margin account details (SIGNED, SAPI).
+// Details: https://binance-docs.github.io/apidocs/spot/en/#query-cross-margin-account-details-user_data
+func (bc *BinanceClient) GetMarginAccount() (MarginAccount, Warning, error) {
+	var marginAccount MarginAccount
+
+	warning, err := bc.newSignedRequest(http.MethodGet).Do("/sapi/v1/margin/account", 10, &marginAccount)
+
+	if err != nil || warning != nil {
+		return MarginAccount{}, warning, err
+	}
+
+	return marginAccount, nil, nil
+}
@@ -0,0 +1,53 @@
+package bncclient
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// doerFunc -- adapter which allows to use ordinary function as Doer.
+type doerFunc func(request *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+func jsonResponse(request *http.Request, body string) *http.Response {
+	return &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Request:    request,
+	}
+}
+
+func TestScientificNotationPrices(t *testing.T) {
+	bc := newUnlimitedClient(doerFunc(func(request *http.Request) (*http.Response, error) {
+		return jsonResponse(request, `{"lastUpdateId":1,"bids":[["8.0E-8","1.23e10"]],"asks":[["1.23e10","8.0E-8"]]}`), nil
+	}))
+
+	orderBook, warning, err := bc.GetOrderBook("SHIBUSDT", 5)
+	if err != nil || warning != nil {
+		t.Fatalf("unexpected warning %v or error %v", warning, err)
+	}
+
+	if bid := orderBook.Bids[0]; bid.Price != 8.0e-8 || bid.Qty != 1.23e10 {
+		t.Errorf("unexpected bid: %+v", bid)
+	}
+
+	if ask := orderBook.Asks[0]; ask.Price != 1.23e10 || ask.Qty != 8.0e-8 {
+		t.Errorf("unexpected ask: %+v", ask)
+	}
+}
+
+func TestOrderBookNotANumber(t *testing.T) {
+	bc := newUnlimitedClient(doerFunc(func(request *http.Request) (*http.Response, error) {
+		return jsonResponse(request, `{"lastUpdateId":1,"bids":[["0.1","1"]],"asks":[["abc","1"]]}`), nil
+	}))
+
+	if _, _, err := bc.GetOrderBook("BTCUSDT", 5); err == nil {
+		t.Fatal("expected error for not a number price instead of zero value")
+	}
+}
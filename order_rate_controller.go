@@ -0,0 +1,118 @@
+package bncclient
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Default order rate limits of Binance spot API, actual ones can be applied from exchangeInfo (see ApplyRateLimits).
+var defaultOrderRateLimits = []RateLimit{
+	{RateLimitType: "ORDERS", Interval: "SECOND", IntervalNum: 10, Limit: 50},
+	{RateLimitType: "ORDERS", Interval: "DAY", IntervalNum: 1, Limit: 160000},
+}
+
+// orderRateController -- "order counter", parallel to weight controller: order placement is limited separately
+// from request weight (and per account, not per IP). Windows are aligned to multiples of their duration since epoch,
+// like Binance counts them.
+type orderRateController struct {
+	windows []orderRateWindow
+	clock   func() time.Time
+	mutex   sync.Mutex
+}
+
+type orderRateWindow struct {
+	limit      int
+	durationMS int64
+	startMS    int64 // Start of the current window
+	count      int   // Orders placed in the current window
+}
+
+func newOrderRateController() *orderRateController {
+	controller := &orderRateController{clock: time.Now}
+	controller.setLimits(defaultOrderRateLimits)
+
+	return controller
+}
+
+// setLimits -- replaces windows by ORDERS entries of given rate limits (other entries are ignored).
+// Returns number of applied entries, nothing is changed if there are no valid ORDERS entries.
+func (orc *orderRateController) setLimits(rateLimits []RateLimit) int {
+	windows := make([]orderRateWindow, 0)
+
+	for _, rateLimit := range rateLimits {
+		if rateLimit.RateLimitType != "ORDERS" || rateLimit.Duration() <= 0 || rateLimit.Limit <= 0 {
+			continue
+		}
+
+		windows = append(windows, orderRateWindow{limit: rateLimit.Limit, durationMS: rateLimit.Duration().Milliseconds()})
+	}
+
+	if len(windows) == 0 {
+		return 0
+	}
+
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].durationMS < windows[j].durationMS
+	})
+
+	orc.mutex.Lock()
+	defer orc.mutex.Unlock()
+
+	orc.windows = windows
+
+	return len(windows)
+}
+
+// acquire -- atomically checks if ordersCount orders fit into all windows. If they fit, they are accounted
+// and 0 is returned, otherwise nothing is accounted, and returned value is the time (ms) to wait.
+func (orc *orderRateController) acquire(ordersCount int) int64 {
+	orc.mutex.Lock()
+	defer orc.mutex.Unlock()
+
+	nowMS := timeToMS(orc.clock())
+	waitMS := int64(0)
+
+	for i := range orc.windows {
+		window := &orc.windows[i]
+		windowStartMS := nowMS - nowMS%window.durationMS
+
+		if window.startMS != windowStartMS {
+			window.startMS = windowStartMS
+			window.count = 0
+		}
+
+		if window.count+ordersCount > window.limit {
+			if windowWaitMS := windowStartMS + window.durationMS - nowMS; windowWaitMS > waitMS {
+				waitMS = windowWaitMS
+			}
+		}
+	}
+
+	if waitMS > 0 {
+		return waitMS
+	}
+
+	for i := range orc.windows {
+		orc.windows[i].count += ordersCount
+	}
+
+	return 0
+}
+
+// acquireOrderRate -- accounts ordersCount new orders in order rate controller. Returns Warning, if order rate limit
+// would be exceeded (then the orders must not be sent). Orders are not accounted in dry-run mode.
+func (bc *BinanceClient) acquireOrderRate(ordersCount int) Warning {
+	if bc.dryRun.interceptsWrites() {
+		return nil
+	}
+
+	waitMS := bc.orderRateController.acquire(ordersCount)
+
+	if waitMS == 0 {
+		return nil
+	}
+
+	return newWarningWithCause(WarnRateLimit, waitMS, fmt.Sprintf("Order rate limit reached. We should wait %d sec before placing new orders.\n", waitMS/1000), ErrRateLimited)
+}
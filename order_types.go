@@ -0,0 +1,106 @@
+package bncclient
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OrderSide -- side of order, use predefined constants or ParseOrderSide for user input.
+type OrderSide string
+
+const (
+	SideBuy  OrderSide = "BUY"
+	SideSell OrderSide = "SELL"
+)
+
+// OrderType -- type of order, use predefined constants or ParseOrderType for user input.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/enums.md#order-types-ordertypes-type
+type OrderType string
+
+const (
+	OrderTypeLimit           OrderType = "LIMIT"
+	OrderTypeMarket          OrderType = "MARKET"
+	OrderTypeStopLoss        OrderType = "STOP_LOSS"
+	OrderTypeStopLossLimit   OrderType = "STOP_LOSS_LIMIT"
+	OrderTypeTakeProfit      OrderType = "TAKE_PROFIT"
+	OrderTypeTakeProfitLimit OrderType = "TAKE_PROFIT_LIMIT"
+	OrderTypeLimitMaker      OrderType = "LIMIT_MAKER"
+)
+
+// TimeInForce -- how long order remains active, use predefined constants or ParseTimeInForce for user input.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/enums.md#time-in-force-timeinforce
+type TimeInForce string
+
+const (
+	TimeInForceGTC TimeInForce = "GTC" // Good Til Canceled
+	TimeInForceIOC TimeInForce = "IOC" // Immediate Or Cancel
+	TimeInForceFOK TimeInForce = "FOK" // Fill or Kill
+)
+
+// ParseOrderSide converts user input (like "buy" or "SELL", case-insensitive) to OrderSide.
+func ParseOrderSide(side string) (OrderSide, error) {
+	orderSide := OrderSide(strings.ToUpper(strings.TrimSpace(side)))
+
+	if !orderSide.IsValid() {
+		return "", fmt.Errorf("%w: not allowed order side: %s", ErrInvalidOrder, side)
+	}
+
+	return orderSide, nil
+}
+
+// IsValid checks if side is one of sides supported by Binance.
+func (s OrderSide) IsValid() bool {
+	return s == SideBuy || s == SideSell
+}
+
+// String implements fmt.Stringer.
+func (s OrderSide) String() string {
+	return string(s)
+}
+
+// ParseOrderType converts user input (like "limit" or "STOP_LOSS_LIMIT", case-insensitive) to OrderType.
+func ParseOrderType(orderType string) (OrderType, error) {
+	parsedType := OrderType(strings.ToUpper(strings.TrimSpace(orderType)))
+
+	if !parsedType.IsValid() {
+		return "", fmt.Errorf("%w: not allowed order type: %s", ErrInvalidOrder, orderType)
+	}
+
+	return parsedType, nil
+}
+
+// IsValid checks if order type is one of types supported by Binance spot API.
+func (t OrderType) IsValid() bool {
+	switch t {
+	case OrderTypeLimit, OrderTypeMarket, OrderTypeStopLoss, OrderTypeStopLossLimit, OrderTypeTakeProfit, OrderTypeTakeProfitLimit, OrderTypeLimitMaker:
+		return true
+	default:
+		return false
+	}
+}
+
+// String implements fmt.Stringer.
+func (t OrderType) String() string {
+	return string(t)
+}
+
+// ParseTimeInForce converts user input (like "gtc", case-insensitive) to TimeInForce.
+func ParseTimeInForce(timeInForce string) (TimeInForce, error) {
+	parsedTimeInForce := TimeInForce(strings.ToUpper(strings.TrimSpace(timeInForce)))
+
+	if !parsedTimeInForce.IsValid() {
+		return "", fmt.Errorf("%w: not allowed time in force: %s", ErrInvalidOrder, timeInForce)
+	}
+
+	return parsedTimeInForce, nil
+}
+
+// IsValid checks if time in force is one of values supported by Binance.
+func (tif TimeInForce) IsValid() bool {
+	return tif == TimeInForceGTC || tif == TimeInForceIOC || tif == TimeInForceFOK
+}
+
+// String implements fmt.Stringer.
+func (tif TimeInForce) String() string {
+	return string(tif)
+}
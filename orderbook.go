@@ -0,0 +1,277 @@
+package bncclient
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const orderBooksMaxConcurrency = 10 // How many order book requests GetOrderBooks performs simultaneously
+
+// OrderBooksError -- combined error of GetOrderBooks, keyed by symbol.
+type OrderBooksError map[string]error
+
+func (e OrderBooksError) Error() string {
+	symbols := make([]string, 0, len(e))
+	for symbol := range e {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	messages := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		messages = append(messages, fmt.Sprintf("%s: %s", symbol, e[symbol].Error()))
+	}
+
+	return fmt.Sprintf("Failed to get order books for %d symbol(s): %s", len(e), strings.Join(messages, "; "))
+}
+
+// GetOrderBooks - gets order books for many symbols concurrently (but not more than 10 requests at once).
+// Every request is accounted in weight controller. If weight limit is reached in the middle of the batch, the rest of
+// symbols is not requested, and already received order books are returned together with Warning, so the caller can
+// sleep and request the missing symbols later.
+// Errors of particular symbols don't stop the batch, they are combined into OrderBooksError (keyed by symbol).
+// Limit is validated like in GetOrderBook, not allowed value results in ErrInvalidLimit.
+func (bc *BinanceClient) GetOrderBooks(symbols []string, limit int) (map[string]OrderBook, Warning, error) {
+	if err := validateLimit(limit, maxOrderBookLimit); err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		mutex        sync.Mutex
+		waitGroup    sync.WaitGroup
+		orderBooks   = make(map[string]OrderBook, len(symbols))
+		symbolErrors = make(OrderBooksError)
+		firstWarning Warning
+	)
+
+	semaphore := make(chan struct{}, orderBooksMaxConcurrency)
+
+	for _, symbol := range symbols {
+		mutex.Lock()
+		budgetExhausted := firstWarning != nil
+		mutex.Unlock()
+
+		if budgetExhausted {
+			break
+		}
+
+		semaphore <- struct{}{}
+		waitGroup.Add(1)
+
+		go func(symbol string) {
+			defer waitGroup.Done()
+			defer func() { <-semaphore }()
+
+			orderBook, warning, err := bc.GetOrderBook(symbol, limit)
+			warning, err = splitWarning(warning, err)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			switch {
+			case err != nil:
+				symbolErrors[symbol] = err
+			case warning != nil:
+				if firstWarning == nil {
+					firstWarning = warning
+				}
+			default:
+				orderBooks[symbol] = orderBook
+			}
+		}(symbol)
+	}
+
+	waitGroup.Wait()
+
+	if len(symbolErrors) > 0 {
+		return orderBooks, firstWarning, symbolErrors
+	}
+
+	if firstWarning != nil && bc.warningsAsErrors {
+		return orderBooks, nil, firstWarning
+	}
+
+	return orderBooks, firstWarning, nil
+}
+
+// SortedBids returns copy of bids, guaranteed to be sorted by price descending (best bid first).
+func (ob OrderBook) SortedBids() []PriceLevel {
+	bids := make([]PriceLevel, len(ob.Bids))
+	copy(bids, ob.Bids)
+
+	sort.SliceStable(bids, func(i, j int) bool {
+		return bids[i].Price > bids[j].Price
+	})
+
+	return bids
+}
+
+// SortedAsks returns copy of asks, guaranteed to be sorted by price ascending (best ask first).
+func (ob OrderBook) SortedAsks() []PriceLevel {
+	asks := make([]PriceLevel, len(ob.Asks))
+	copy(asks, ob.Asks)
+
+	sort.SliceStable(asks, func(i, j int) bool {
+		return asks[i].Price < asks[j].Price
+	})
+
+	return asks
+}
+
+// IsEmpty returns true if at least one side of the book has no levels, i.e. there is no valid best bid/ask pair
+// and the book should not be used for pricing.
+func (ob OrderBook) IsEmpty() bool {
+	return len(ob.Bids) == 0 || len(ob.Asks) == 0
+}
+
+// IsCrossed returns true if the best bid is not lower than the best ask. It never happens in Binance snapshot,
+// but locally maintained book can be crossed for a moment during fast updates, and should not be used for pricing then.
+// Empty book (see IsEmpty) is not crossed.
+func (ob OrderBook) IsCrossed() bool {
+	if ob.IsEmpty() {
+		return false
+	}
+
+	return ob.SortedBids()[0].Price >= ob.SortedAsks()[0].Price
+}
+
+// CumulativeBids returns bids sorted from the best price, where Qty of every level is total quantity available
+// at this price and all better prices.
+func (ob OrderBook) CumulativeBids() []PriceLevel {
+	return cumulativeLevels(ob.SortedBids())
+}
+
+// CumulativeAsks returns asks sorted from the best price, where Qty of every level is total quantity available
+// at this price and all better prices.
+func (ob OrderBook) CumulativeAsks() []PriceLevel {
+	return cumulativeLevels(ob.SortedAsks())
+}
+
+func cumulativeLevels(levels []PriceLevel) []PriceLevel {
+	totalQty := 0.0
+	for i := range levels {
+		totalQty += levels[i].Qty
+		levels[i].Qty = totalQty
+	}
+
+	return levels
+}
+
+// EstimateFillPrice estimates average price of market order of given quantity by walking the book from the best price
+// (BUY order takes asks, SELL order takes bids). filled is less than qty if the book is not deep enough,
+// avgPrice is 0 if nothing can be filled (empty book side, unknown side or not positive qty).
+func (ob OrderBook) EstimateFillPrice(side OrderSide, qty float64) (avgPrice float64, filled float64) {
+	var levels []PriceLevel
+
+	switch side {
+	case SideBuy:
+		levels = ob.SortedAsks()
+	case SideSell:
+		levels = ob.SortedBids()
+	default:
+		return 0, 0
+	}
+
+	notional := 0.0
+
+	for _, level := range levels {
+		if filled >= qty {
+			break
+		}
+
+		levelQty := math.Min(level.Qty, qty-filled)
+		notional += levelQty * level.Price
+		filled += levelQty
+	}
+
+	if filled <= 0 {
+		return 0, 0
+	}
+
+	return notional / filled, filled
+}
+
+// OrderBookMap -- order book as price -> quantity maps, for quick lookup of levels by price.
+// Note that keys are float64: prices parsed from the same Binance string are always equal, but computed prices
+// (like bestBid + tickSize) may differ in the last bit, so snap them with SymbolInfo.RoundPrice before lookup.
+type OrderBookMap struct {
+	LastUpdateId int64
+	Bids         map[float64]float64
+	Asks         map[float64]float64
+}
+
+// AsMap converts order book to OrderBookMap. If the same price occurs several times, the last quantity wins.
+func (ob OrderBook) AsMap() OrderBookMap {
+	orderBookMap := OrderBookMap{
+		LastUpdateId: ob.LastUpdateId,
+		Bids:         make(map[float64]float64, len(ob.Bids)),
+		Asks:         make(map[float64]float64, len(ob.Asks)),
+	}
+
+	for _, level := range ob.Bids {
+		orderBookMap.Bids[level.Price] = level.Qty
+	}
+
+	for _, level := range ob.Asks {
+		orderBookMap.Asks[level.Price] = level.Qty
+	}
+
+	return orderBookMap
+}
+
+// BatchResult -- common shape of results of multi-call helpers which stop when weight limit is reached in the middle
+// of the batch (GetKlinesRangeBatch, GetOrderBooksBatch). Every result has Data (accumulated so far), Resume
+// (cursor to continue from, its type depends on the helper) and Warning fields.
+type BatchResult interface {
+	GetWarning() Warning
+	IsComplete() bool
+}
+
+// OrderBooksBatchResult -- result of GetOrderBooksBatch (see BatchResult).
+// Resume contains symbols which were not requested yet (empty when complete).
+type OrderBooksBatchResult struct {
+	Data    map[string]OrderBook
+	Resume  []string
+	Warning Warning
+}
+
+func (r OrderBooksBatchResult) GetWarning() Warning {
+	return r.Warning
+}
+
+func (r OrderBooksBatchResult) IsComplete() bool {
+	return len(r.Resume) == 0
+}
+
+// GetOrderBooksBatch - the same as GetOrderBooks, but additionally returns symbols which were not requested because
+// weight limit was reached, so the caller can sleep and call it again with result.Resume as symbols.
+// Symbols which failed with error are not resumed, they are reported in OrderBooksError.
+func (bc *BinanceClient) GetOrderBooksBatch(symbols []string, limit int) (OrderBooksBatchResult, error) {
+	orderBooks, warning, err := bc.GetOrderBooks(symbols, limit)
+	warning, err = splitWarning(warning, err)
+
+	symbolErrors, isOrderBooksError := err.(OrderBooksError)
+	if err != nil && !isOrderBooksError {
+		return OrderBooksBatchResult{}, err
+	}
+
+	resume := make([]string, 0)
+	for _, symbol := range symbols {
+		_, received := orderBooks[symbol]
+		_, failed := symbolErrors[symbol]
+		if !received && !failed {
+			resume = append(resume, symbol)
+		}
+	}
+
+	result := OrderBooksBatchResult{Data: orderBooks, Resume: resume, Warning: warning}
+
+	if isOrderBooksError {
+		return result, symbolErrors
+	}
+
+	return result, nil
+}
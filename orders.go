@@ -0,0 +1,396 @@
+package bncclient
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// OrderResponse -- order state, as returned by order endpoints (place, cancel, query).
+type OrderResponse struct {
+	Symbol                  string      `json:"symbol"`
+	OrderId                 int64       `json:"orderId"`
+	OrderListId             int64       `json:"orderListId"`
+	ClientOrderId           string      `json:"clientOrderId"`
+	OrigClientOrderId       string      `json:"origClientOrderId"`
+	TransactTime            int64       `json:"transactTime"`
+	Price                   float64     `json:"price,string"`
+	OrigQty                 float64     `json:"origQty,string"`
+	ExecutedQty             float64     `json:"executedQty,string"`
+	CummulativeQuoteQty     float64     `json:"cummulativeQuoteQty,string"`
+	Status                  string      `json:"status"`
+	TimeInForce             TimeInForce `json:"timeInForce"`
+	Type                    OrderType   `json:"type"`
+	Side                    OrderSide   `json:"side"`
+	StopPrice               float64     `json:"stopPrice,string"`
+	SelfTradePreventionMode string      `json:"selfTradePreventionMode"`
+}
+
+// CancelAllOpenOrders - cancels all active orders on a symbol (including OCO orders). Useful as a kill-switch.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#cancel-all-open-orders-on-a-symbol-trade
+// If there are no open orders, empty list is returned (not an error).
+// Note: orders which are part of OCO are returned as separate items too, but fields specific to order lists are not parsed.
+func (bc *BinanceClient) CancelAllOpenOrders(symbol string) ([]OrderResponse, Warning, error) {
+	var cancelledOrders []OrderResponse
+
+	warning, err := bc.newSignedRequest(http.MethodDelete).
+		AddString("symbol", symbol).
+		DoArray("/api/v3/openOrders", 1, &cancelledOrders)
+
+	if isUnknownOrderError(err) { // Binance answers with "Unknown order sent" when there are no open orders
+		return []OrderResponse{}, nil, nil
+	}
+
+	if err != nil || warning != nil {
+		return nil, warning, err
+	}
+
+	if cancelledOrders == nil {
+		cancelledOrders = []OrderResponse{}
+	}
+
+	return cancelledOrders, nil, nil
+}
+
+// isUnknownOrderError checks if err is Binance error -2011 "Unknown order sent".
+func isUnknownOrderError(err error) bool {
+	return errors.Is(err, ErrorCode(-2011))
+}
+
+// OrderRequest -- parameters of new order. Which fields are required depends on Type:
+//
+//	LIMIT: TimeInForce, Quantity, Price
+//	MARKET: Quantity or QuoteOrderQty
+//	STOP_LOSS, TAKE_PROFIT: Quantity, StopPrice
+//	STOP_LOSS_LIMIT, TAKE_PROFIT_LIMIT: TimeInForce, Quantity, Price, StopPrice
+//	LIMIT_MAKER: Quantity, Price
+//
+// Zero values mean "not specified". NewClientOrderId is optional (empty means PlaceOrder generates random UUID).
+type OrderRequest struct {
+	Symbol           string
+	Side             OrderSide
+	Type             OrderType
+	TimeInForce      TimeInForce
+	Quantity         float64
+	QuoteOrderQty    float64
+	Price            float64
+	StopPrice        float64
+	NewClientOrderId string
+}
+
+// PlaceOrder - places new order. Request is validated before sending, so typo in side/type/timeInForce or missing
+// mandatory parameter is reported as ErrInvalidOrder instead of Binance error. When order rate limit (50 orders per 10s
+// and 160000 per day by default, see ApplyRateLimits) would be exceeded, Warning is returned and order is not sent.
+// If NewClientOrderId is not set, it is generated, and returned in ClientOrderId of the response even when Warning or
+// error is returned. So if the order status is unknown (network failure), it can be retried with the same
+// NewClientOrderId (or looked up by it) without risk of placing a duplicate order.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#new-order-trade
+func (bc *BinanceClient) PlaceOrder(req OrderRequest) (OrderResponse, Warning, error) {
+	if err := req.validate(); err != nil {
+		return OrderResponse{}, nil, err
+	}
+
+	if req.NewClientOrderId == "" {
+		clientOrderId, err := newClientOrderId()
+		if err != nil {
+			return OrderResponse{}, nil, err
+		}
+		req.NewClientOrderId = clientOrderId
+	}
+
+	failedOrder := OrderResponse{Symbol: req.Symbol, ClientOrderId: req.NewClientOrderId}
+
+	if warning := bc.acquireOrderRate(1); warning != nil {
+		if bc.warningsAsErrors {
+			return failedOrder, nil, warning
+		}
+		return failedOrder, warning, nil
+	}
+
+	var orderResponse OrderResponse
+
+	warning, err := bc.newSignedRequest(http.MethodPost).
+		AddString("symbol", req.Symbol).
+		AddString("side", req.Side.String()).
+		AddString("type", req.Type.String()).
+		AddString("newOrderRespType", "RESULT").
+		AddString("timeInForce", req.TimeInForce.String()).
+		AddFloat("quantity", req.Quantity).
+		AddFloat("quoteOrderQty", req.QuoteOrderQty).
+		AddFloat("price", req.Price).
+		AddFloat("stopPrice", req.StopPrice).
+		AddString("newClientOrderId", req.NewClientOrderId).
+		Do("/api/v3/order", 1, &orderResponse)
+
+	if err != nil || warning != nil {
+		return failedOrder, warning, err
+	}
+
+	if orderResponse.ClientOrderId == "" { // For example, in dry-run mode
+		orderResponse.ClientOrderId = req.NewClientOrderId
+	}
+
+	return orderResponse, nil, nil
+}
+
+// newClientOrderId generates random (version 4) UUID, which satisfies Binance format of client order id.
+func newClientOrderId() (string, error) {
+	uuid := make([]byte, 16)
+	if _, err := rand.Read(uuid); err != nil {
+		return "", err
+	}
+
+	uuid[6] = (uuid[6] & 0x0f) | 0x40 // Version 4
+	uuid[8] = (uuid[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16]), nil
+}
+
+func (req OrderRequest) validate() error {
+	if req.Symbol == "" {
+		return fmt.Errorf("%w: symbol is required", ErrInvalidOrder)
+	}
+
+	if !req.Side.IsValid() {
+		return fmt.Errorf("%w: not allowed order side: \"%s\"", ErrInvalidOrder, req.Side)
+	}
+
+	if !req.Type.IsValid() {
+		return fmt.Errorf("%w: not allowed order type: \"%s\"", ErrInvalidOrder, req.Type)
+	}
+
+	if req.TimeInForce != "" && !req.TimeInForce.IsValid() {
+		return fmt.Errorf("%w: not allowed time in force: \"%s\"", ErrInvalidOrder, req.TimeInForce)
+	}
+
+	needsTimeInForce := false
+	needsPrice := false
+	needsStopPrice := false
+
+	switch req.Type {
+	case OrderTypeLimit:
+		needsTimeInForce, needsPrice = true, true
+	case OrderTypeStopLoss, OrderTypeTakeProfit:
+		needsStopPrice = true
+	case OrderTypeStopLossLimit, OrderTypeTakeProfitLimit:
+		needsTimeInForce, needsPrice, needsStopPrice = true, true, true
+	case OrderTypeLimitMaker:
+		needsPrice = true
+	}
+
+	if req.Type == OrderTypeMarket {
+		if (req.Quantity > 0) == (req.QuoteOrderQty > 0) {
+			return fmt.Errorf("%w: MARKET order requires either quantity or quoteOrderQty", ErrInvalidOrder)
+		}
+	} else if req.Quantity <= 0 {
+		return fmt.Errorf("%w: %s order requires positive quantity", ErrInvalidOrder, req.Type)
+	}
+
+	if needsTimeInForce && req.TimeInForce == "" {
+		return fmt.Errorf("%w: %s order requires timeInForce", ErrInvalidOrder, req.Type)
+	}
+
+	if needsPrice && req.Price <= 0 {
+		return fmt.Errorf("%w: %s order requires positive price", ErrInvalidOrder, req.Type)
+	}
+
+	if needsStopPrice && req.StopPrice <= 0 {
+		return fmt.Errorf("%w: %s order requires positive stopPrice", ErrInvalidOrder, req.Type)
+	}
+
+	return nil
+}
+
+// OCORequest -- parameters of One-Cancels-the-Other order: LIMIT_MAKER leg (Price) and STOP_LOSS(_LIMIT) leg (StopPrice).
+// StopLimitPrice is optional (0 means STOP_LOSS leg instead of STOP_LOSS_LIMIT), if it's set, StopLimitTimeInForce is required.
+// Client order ids are optional too (empty means PlaceOCOOrder generates random UUIDs).
+type OCORequest struct {
+	Symbol               string
+	Side                 OrderSide
+	Quantity             float64
+	Price                float64
+	StopPrice            float64
+	StopLimitPrice       float64
+	StopLimitTimeInForce TimeInForce
+	ListClientOrderId    string
+	LimitClientOrderId   string
+	StopClientOrderId    string
+}
+
+// OCOResponse -- order list created by PlaceOCOOrder. Orders contains ids of both legs, OrderReports - their full state.
+type OCOResponse struct {
+	OrderListId       int64  `json:"orderListId"`
+	ContingencyType   string `json:"contingencyType"`
+	ListStatusType    string `json:"listStatusType"`
+	ListOrderStatus   string `json:"listOrderStatus"`
+	ListClientOrderId string `json:"listClientOrderId"`
+	TransactionTime   int64  `json:"transactionTime"`
+	Symbol            string `json:"symbol"`
+	Orders            []struct {
+		Symbol        string `json:"symbol"`
+		OrderId       int64  `json:"orderId"`
+		ClientOrderId string `json:"clientOrderId"`
+	} `json:"orders"`
+	OrderReports []OrderResponse `json:"orderReports"`
+}
+
+// PlaceOCOOrder - places One-Cancels-the-Other order (bracket order): when one leg is filled or triggered, the other is cancelled.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#new-oco-trade
+// Binance requires limit price > last price > stop price for SELL and limit price < last price < stop price for BUY.
+// Only the part which doesn't depend on the market is validated before sending (for SELL limit price must be above
+// stop price, for BUY - below it), position relative to the last price is checked by Binance.
+// Client order ids which are not set (of the list and of both legs) are generated, like in PlaceOrder, and
+// ListClientOrderId is returned in the response even when Warning or error is returned, so the order list can be
+// looked up by it if its status is unknown.
+func (bc *BinanceClient) PlaceOCOOrder(req OCORequest) (OCOResponse, Warning, error) {
+	if err := req.validate(); err != nil {
+		return OCOResponse{}, nil, err
+	}
+
+	for _, clientOrderId := range []*string{&req.ListClientOrderId, &req.LimitClientOrderId, &req.StopClientOrderId} {
+		if *clientOrderId == "" {
+			generatedId, err := newClientOrderId()
+			if err != nil {
+				return OCOResponse{}, nil, err
+			}
+			*clientOrderId = generatedId
+		}
+	}
+
+	failedOrderList := OCOResponse{Symbol: req.Symbol, ListClientOrderId: req.ListClientOrderId}
+
+	if warning := bc.acquireOrderRate(2); warning != nil { // Both legs are counted
+		if bc.warningsAsErrors {
+			return failedOrderList, nil, warning
+		}
+		return failedOrderList, warning, nil
+	}
+
+	request := bc.newSignedRequest(http.MethodPost).
+		AddString("symbol", req.Symbol).
+		AddString("side", req.Side.String()).
+		AddFloat("quantity", req.Quantity).
+		AddFloat("price", req.Price).
+		AddFloat("stopPrice", req.StopPrice).
+		AddString("listClientOrderId", req.ListClientOrderId).
+		AddString("limitClientOrderId", req.LimitClientOrderId).
+		AddString("stopClientOrderId", req.StopClientOrderId)
+
+	if req.StopLimitPrice > 0 {
+		request.AddFloat("stopLimitPrice", req.StopLimitPrice).AddString("stopLimitTimeInForce", req.StopLimitTimeInForce.String())
+	}
+
+	var ocoResponse OCOResponse
+
+	warning, err := request.Do("/api/v3/order/oco", 1, &ocoResponse)
+
+	if err != nil || warning != nil {
+		return failedOrderList, warning, err
+	}
+
+	if ocoResponse.ListClientOrderId == "" { // For example, in dry-run mode
+		ocoResponse.ListClientOrderId = req.ListClientOrderId
+	}
+
+	return ocoResponse, nil, nil
+}
+
+func (req OCORequest) validate() error {
+	if req.Symbol == "" {
+		return fmt.Errorf("%w: symbol is required", ErrInvalidOrder)
+	}
+
+	if req.Quantity <= 0 || req.Price <= 0 || req.StopPrice <= 0 {
+		return fmt.Errorf("%w: quantity, price and stopPrice must be positive", ErrInvalidOrder)
+	}
+
+	switch req.Side {
+	case SideSell:
+		if req.Price <= req.StopPrice {
+			return fmt.Errorf("%w: for SELL OCO limit price (%v) must be greater than stop price (%v)", ErrInvalidOrder, req.Price, req.StopPrice)
+		}
+	case SideBuy:
+		if req.Price >= req.StopPrice {
+			return fmt.Errorf("%w: for BUY OCO limit price (%v) must be less than stop price (%v)", ErrInvalidOrder, req.Price, req.StopPrice)
+		}
+	default:
+		return fmt.Errorf("%w: side must be BUY or SELL, got \"%s\"", ErrInvalidOrder, req.Side)
+	}
+
+	if req.StopLimitPrice > 0 && !req.StopLimitTimeInForce.IsValid() {
+		return fmt.Errorf("%w: valid stopLimitTimeInForce is required when stopLimitPrice is set, got \"%s\"", ErrInvalidOrder, req.StopLimitTimeInForce)
+	}
+
+	return nil
+}
+
+// formatFloat formats price/quantity for request parameters without exponent and without trailing zeros.
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+const preventedMatchesMaxLimit = 1000 // Binance returns maximum 1000 prevented matches per request
+
+// PreventedMatchesOptions -- query of GetPreventedMatches: either PreventedMatchId, or OrderId (optionally with
+// FromPreventedMatchId for pagination) must be set.
+type PreventedMatchesOptions struct {
+	PreventedMatchId     *int64
+	OrderId              *int64
+	FromPreventedMatchId *int64
+	Limit                int // Allowed values: [1, 1000]. 0 means default limit (500).
+}
+
+// PreventedMatch -- order which expired because of self-trade prevention (STP).
+type PreventedMatch struct {
+	Symbol                  string  `json:"symbol"`
+	PreventedMatchId        int64   `json:"preventedMatchId"`
+	TakerOrderId            int64   `json:"takerOrderId"`
+	MakerSymbol             string  `json:"makerSymbol"`
+	MakerOrderId            int64   `json:"makerOrderId"`
+	TradeGroupId            int64   `json:"tradeGroupId"`
+	SelfTradePreventionMode string  `json:"selfTradePreventionMode"`
+	Price                   float64 `json:"price,string"`
+	MakerPreventedQuantity  float64 `json:"makerPreventedQuantity,string"`
+	TransactTime            int64   `json:"transactTime"`
+}
+
+// GetPreventedMatches - orders expired because of self-trade prevention, for reconciliation against expected fills.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#query-prevented-matches-user_data
+// Weight is 2 when queried by PreventedMatchId, and 20 when queried by OrderId.
+func (bc *BinanceClient) GetPreventedMatches(symbol string, opts PreventedMatchesOptions) ([]PreventedMatch, Warning, error) {
+	if (opts.PreventedMatchId == nil) == (opts.OrderId == nil) {
+		return nil, nil, errors.New("exactly one of preventedMatchId and orderId is required")
+	}
+
+	limit := opts.Limit
+	if limit == 0 {
+		limit = -1
+	}
+
+	if err := validateLimit(limit, preventedMatchesMaxLimit); err != nil {
+		return nil, nil, err
+	}
+
+	var preventedMatches []PreventedMatch
+
+	weight := 2
+	if opts.OrderId != nil {
+		weight = 20
+	}
+
+	warning, err := bc.newSignedRequest(http.MethodGet).
+		AddString("symbol", symbol).
+		AddOptionalInt64("preventedMatchId", opts.PreventedMatchId).
+		AddOptionalInt64("orderId", opts.OrderId).
+		AddOptionalInt64("fromPreventedMatchId", opts.FromPreventedMatchId).
+		AddInt("limit", limit, true).
+		DoArray("/api/v3/myPreventedMatches", weight, &preventedMatches)
+
+	if err != nil || warning != nil {
+		return nil, warning, err
+	}
+
+	return preventedMatches, nil, nil
+}
@@ -0,0 +1,50 @@
+package bncclient
+
+import (
+	"net/http"
+)
+
+// RequestPriority -- priority of requests in weight controller (see SetHighPriorityReserve).
+type RequestPriority int
+
+const (
+	PriorityNormal RequestPriority = iota // Default: high for order requests (signed POST, PUT, DELETE), normal for the rest
+	PriorityHigh                          // Requests can use weight reserved for high priority
+)
+
+// SetHighPriorityReserve - reserves part of weight limit for high priority requests, so background market data polling
+// can't exhaust the whole budget and block trading: normal priority requests get a Warning when accumulated weight
+// reaches (limit - reserve), while high priority requests can go up to the limit. Orders (signed POST, PUT, DELETE
+// requests) are always high priority, other requests can be made high priority with WithPriority.
+// Zero reserve (default) disables priority lanes.
+// Note: weight controller is shared by all clients, so the reserve is changed for all of them. Keys of the key pool
+// get the reserve too, but only keys added before the call.
+func (bc *BinanceClient) SetHighPriorityReserve(weight int) {
+	bc.weightController.setHighPriorityReserve(weight)
+	bc.sapiWeightController.setHighPriorityReserve(weight)
+
+	if bc.keyPool != nil {
+		bc.keyPool.mutex.Lock()
+		defer bc.keyPool.mutex.Unlock()
+
+		for _, key := range bc.keyPool.keys {
+			key.weightController.setHighPriorityReserve(weight)
+		}
+	}
+}
+
+// WithPriority - returns copy of the client, which makes requests with given priority (see SetHighPriorityReserve).
+// Like WithContext, it's a shallow copy: default headers, HTTP client with its transport and all shared state
+// (weight controllers, caches, streams) stay common with the original, so only plain settings changed on the copy
+// (e.g. SetWarningsAsErrors) don't affect the original.
+func (bc *BinanceClient) WithPriority(priority RequestPriority) *BinanceClient {
+	clientCopy := *bc
+	clientCopy.priority = priority
+
+	return &clientCopy
+}
+
+// isHighPriority checks if request can use weight reserved for high priority requests.
+func (bc *BinanceClient) isHighPriority(method string, signed bool) bool {
+	return bc.priority == PriorityHigh || (signed && method != http.MethodGet)
+}
@@ -0,0 +1,160 @@
+package bncclient
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ResponseMeta -- metadata of HTTP response, for latency measurement and clock drift detection per request.
+type ResponseMeta struct {
+	StatusCode int
+	ServerDate time.Time // Date header of response (second precision), zero if there is no such header
+	SentAt     time.Time // Local time when request was started
+	ReceivedAt time.Time // Local time when response was received
+}
+
+// RoundTrip returns time between sending request and receiving response.
+func (m ResponseMeta) RoundTrip() time.Duration {
+	return m.ReceivedAt.Sub(m.SentAt)
+}
+
+// ClockOffset estimates offset between server clock and local clock (server minus local) from Date header,
+// assuming the server handled request in the middle of round trip. Precision is limited by Date header (1 second),
+// second value is false if response has no Date header.
+func (m ResponseMeta) ClockOffset() (time.Duration, bool) {
+	if m.ServerDate.IsZero() {
+		return 0, false
+	}
+
+	return m.ServerDate.Sub(m.SentAt.Add(m.RoundTrip() / 2)), true
+}
+
+// requestBuilder -- collects parameters of API request and performs it with common handling of errors, Warnings
+// and parsing, so every endpoint method doesn't repeat the same boilerplate:
+//
+//	warning, err := bc.newRequest(http.MethodGet).AddString("symbol", symbol).AddInt("limit", limit, true).Do(path, 1, &target)
+type requestBuilder struct {
+	client      *BinanceClient
+	method      string
+	signed      bool
+	queryParams map[string]string
+	headers     http.Header
+	meta        *ResponseMeta // If set, metadata of response is stored there
+}
+
+// newRequest starts building of not signed request.
+func (bc *BinanceClient) newRequest(method string) *requestBuilder {
+	return &requestBuilder{client: bc, method: method, queryParams: make(map[string]string)}
+}
+
+// newSignedRequest starts building of signed request: timestamp and recvWindow are added to parameters,
+// and the whole query string is signed with the client's secret key (see signQuery).
+func (bc *BinanceClient) newSignedRequest(method string) *requestBuilder {
+	builder := bc.newRequest(method)
+	builder.signed = true
+
+	return builder
+}
+
+// AddString adds parameter, empty value is omitted.
+func (rb *requestBuilder) AddString(name string, value string) *requestBuilder {
+	if value != "" {
+		rb.queryParams[name] = value
+	}
+
+	return rb
+}
+
+// AddInt adds parameter, negative value is omitted if omitIfNegative is true (-1 means "not specified" in this package).
+func (rb *requestBuilder) AddInt(name string, value int, omitIfNegative bool) *requestBuilder {
+	return rb.AddInt64(name, int64(value), omitIfNegative)
+}
+
+// AddInt64 adds parameter, negative value is omitted if omitIfNegative is true.
+func (rb *requestBuilder) AddInt64(name string, value int64, omitIfNegative bool) *requestBuilder {
+	if !omitIfNegative || value >= 0 {
+		rb.queryParams[name] = strconv.FormatInt(value, 10)
+	}
+
+	return rb
+}
+
+// AddOptionalInt64 adds parameter, nil value is omitted.
+func (rb *requestBuilder) AddOptionalInt64(name string, value *int64) *requestBuilder {
+	if value != nil {
+		rb.queryParams[name] = strconv.FormatInt(*value, 10)
+	}
+
+	return rb
+}
+
+// AddFloat adds parameter, not positive value is omitted.
+func (rb *requestBuilder) AddFloat(name string, value float64) *requestBuilder {
+	if value > 0 {
+		rb.queryParams[name] = formatFloat(value)
+	}
+
+	return rb
+}
+
+// WithHeader adds request header, empty value is omitted.
+func (rb *requestBuilder) WithHeader(name string, value string) *requestBuilder {
+	if value != "" {
+		if rb.headers == nil {
+			rb.headers = http.Header{}
+		}
+		rb.headers.Set(name, value)
+	}
+
+	return rb
+}
+
+// WithMeta makes the request to store metadata of response to meta.
+func (rb *requestBuilder) WithMeta(meta *ResponseMeta) *requestBuilder {
+	rb.meta = meta
+
+	return rb
+}
+
+// Do performs request and parses response to target (pointer). Warning is returned in Warning position (or in error
+// position in warnings-as-errors mode), target is left untouched in this case.
+func (rb *requestBuilder) Do(path string, weight int, target interface{}) (Warning, error) {
+	return rb.do(path, weight, target, rb.client.tryParseResponse)
+}
+
+// DoArray is Do for endpoints which return JSON array (see tryParseArrayResponse).
+func (rb *requestBuilder) DoArray(path string, weight int, target interface{}) (Warning, error) {
+	return rb.do(path, weight, target, rb.client.tryParseArrayResponse)
+}
+
+// DoRaw performs request and returns raw (not parsed) response with its status code and headers, for endpoints which
+// need special handling of response (conditional requests, streaming decoding etc). When Binance responds with error
+// status, body is returned together with the error (for endpoints which send details in error response).
+func (rb *requestBuilder) DoRaw(path string, weight int) ([]byte, int, http.Header, Warning, error) {
+	sentAt := time.Now()
+	responseRaw, statusCode, header, warning, err := rb.client.makeApiRequestWithHeaders(rb.method, path, rb.client.apiKey, rb.queryParams, weight, rb.headers, rb.signed)
+
+	if rb.meta != nil {
+		*rb.meta = ResponseMeta{StatusCode: statusCode, SentAt: sentAt, ReceivedAt: time.Now()}
+		if serverDate, dateErr := http.ParseTime(header.Get("Date")); dateErr == nil {
+			rb.meta.ServerDate = serverDate
+		}
+	}
+
+	return responseRaw, statusCode, header, warning, err
+}
+
+func (rb *requestBuilder) do(path string, weight int, target interface{}, parse func([]byte, interface{}) error) (Warning, error) {
+	responseRaw, _, _, warning, err := rb.DoRaw(path, weight)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if warning != nil {
+		return warning, nil
+	}
+
+	return nil, parse(responseRaw, target)
+}
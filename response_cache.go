@@ -0,0 +1,112 @@
+package bncclient
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const responseCacheMaxEntries = 1000
+
+// cacheableEndpoints -- GET endpoints with immutable historical data, which responses can be cached (see SetResponseCacheTTL).
+var cacheableEndpoints = map[string]bool{
+	"/api/v3/aggTrades":        true,
+	"/api/v3/historicalTrades": true,
+	"/api/v3/klines":           true,
+	"/api/v3/uiKlines":         true,
+}
+
+// responseCache -- short-living cache of responses of identical repeated requests.
+type responseCache struct {
+	ttl     time.Duration // 0 means caching is disabled
+	entries map[string]responseCacheEntry
+	mutex   sync.Mutex
+}
+
+type responseCacheEntry struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{entries: make(map[string]responseCacheEntry)}
+}
+
+// SetResponseCacheTTL - enables caching of responses of historical market data requests (aggTrades, historicalTrades,
+// klines, uiKlines) for given time, so identical requests repeated within TTL (for example, by re-rendered dashboard)
+// return cached response without network round trip and without charging weight. Only requests bounded by fromId
+// or endTime are cached: requests without them ask for the latest data, which must always be fresh.
+// Zero TTL (default) disables caching and drops cached responses.
+func (bc *BinanceClient) SetResponseCacheTTL(ttl time.Duration) {
+	bc.responseCache.mutex.Lock()
+	defer bc.responseCache.mutex.Unlock()
+
+	bc.responseCache.ttl = ttl
+	bc.responseCache.entries = make(map[string]responseCacheEntry)
+}
+
+// responseCacheKey returns key of the request in response cache, or false if the request must not be cached.
+func responseCacheKey(method string, path string, apiKey string, queryParams map[string]string, signed bool) (string, bool) {
+	if method != http.MethodGet || signed || !cacheableEndpoints[path] {
+		return "", false
+	}
+
+	_, hasFromId := queryParams["fromId"]
+	_, hasEndTime := queryParams["endTime"]
+	if !hasFromId && !hasEndTime {
+		return "", false
+	}
+
+	query := url.Values{}
+	for key, value := range queryParams {
+		query.Set(key, value)
+	}
+
+	// historicalTrades requires API key, so responses are not shared between keys:
+	return apiKey + " " + path + "?" + query.Encode(), true
+}
+
+func (rc *responseCache) get(key string) ([]byte, bool) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	entry, exists := rc.entries[key]
+	if !exists || !time.Now().Before(entry.expiresAt) {
+		return nil, false
+	}
+
+	return entry.body, true
+}
+
+func (rc *responseCache) put(key string, body []byte) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	if rc.ttl <= 0 {
+		return
+	}
+
+	now := time.Now()
+
+	if len(rc.entries) >= responseCacheMaxEntries {
+		for entryKey, entry := range rc.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(rc.entries, entryKey)
+			}
+		}
+	}
+
+	if len(rc.entries) >= responseCacheMaxEntries {
+		rc.entries = make(map[string]responseCacheEntry)
+	}
+
+	rc.entries[key] = responseCacheEntry{body: body, expiresAt: now.Add(rc.ttl)}
+}
+
+func (rc *responseCache) isEnabled() bool {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	return rc.ttl > 0
+}
@@ -0,0 +1,86 @@
+package bncclient
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// assertBinanceError fails the test if err is not native Binance error with given code.
+func assertBinanceError(t *testing.T, err error, code int) {
+	t.Helper()
+
+	var binanceErr binanceError
+	if !errors.As(err, &binanceErr) || binanceErr.Code != code {
+		t.Fatalf("expected Binance error %d, got %v", code, err)
+	}
+}
+
+func TestTryParseResponseErrorShapeFirst(t *testing.T) {
+	bc := NewBinanceClient("")
+	errorBody := []byte(`{"code":-1121,"msg":"Invalid symbol."}`)
+
+	// Error object parses into struct target "successfully" (unknown fields are ignored), so it must be checked first:
+	var orderBook struct {
+		LastUpdateId int64 `json:"lastUpdateId"`
+	}
+	assertBinanceError(t, bc.tryParseResponse(errorBody, &orderBook), -1121)
+
+	var trades TradesList
+	assertBinanceError(t, bc.tryParseResponse(errorBody, &trades), -1121)
+}
+
+func TestTryParseResponseSuccessShapes(t *testing.T) {
+	bc := NewBinanceClient("")
+
+	// Struct target with empty array must not "succeed" into zero value:
+	var orderBook struct {
+		LastUpdateId int64 `json:"lastUpdateId"`
+	}
+	if err := bc.tryParseResponse([]byte(`[]`), &orderBook); err == nil {
+		t.Fatal("struct target: expected error for empty array")
+	}
+
+	// Slice target with empty array is a valid empty result:
+	var trades TradesList
+	if err := bc.tryParseResponse([]byte(`[]`), &trades); err != nil || len(trades) != 0 {
+		t.Fatalf("slice target: expected empty result, got %v, %v", trades, err)
+	}
+
+	// Object which only looks like error (no "msg") is parsed as success type:
+	if err := bc.tryParseResponse([]byte(`{"lastUpdateId":5,"code":0}`), &orderBook); err != nil || orderBook.LastUpdateId != 5 {
+		t.Fatalf("expected successful parsing, got %+v, %v", orderBook, err)
+	}
+}
+
+func TestGetOrderBookMalformedLevel(t *testing.T) {
+	bodies := []string{
+		`{"lastUpdateId":1,"bids":[["4.00000000"]],"asks":[]}`,
+		`{"lastUpdateId":1,"bids":[],"asks":[["4.00000200","12.0"],[]]}`,
+	}
+
+	for _, body := range bodies {
+		bc := NewTestnetClient("test-api-key", "test-secret-key")
+		bc.SetHTTPClient(doerFunc(func(request *http.Request) (*http.Response, error) {
+			return jsonResponse(request, body), nil
+		}))
+
+		if _, _, err := bc.GetOrderBook("BTCUSDT", 5); !errors.Is(err, ErrUnexpectedResponse) {
+			t.Errorf("%s: expected ErrUnexpectedResponse, got %v", body, err)
+		}
+	}
+}
+
+// panickingTarget -- decoding target which panics, like custom decoder which doesn't expect the response shape.
+type panickingTarget struct{}
+
+func (p *panickingTarget) UnmarshalJSON([]byte) error {
+	var levels [][]string
+	return errors.New(levels[0][1]) // Index out of range
+}
+
+func TestTryParseResponseRecoversPanic(t *testing.T) {
+	if err := NewBinanceClient("").tryParseResponse([]byte(`{"bids":[]}`), &panickingTarget{}); !errors.Is(err, ErrUnexpectedResponse) {
+		t.Fatalf("expected panic to be returned as ErrUnexpectedResponse, got %v", err)
+	}
+}
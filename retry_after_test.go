@@ -0,0 +1,91 @@
+package bncclient
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimitRetryAfter(t *testing.T) {
+	testCases := []struct {
+		name            string
+		retryAfter      string
+		expectedRetryMS int64
+	}{
+		{"missing", "", 40 * 1000}, // Rest of the weight window: the first request was made 20s ago
+		{"zero", "0", 40 * 1000},
+		{"valid", "3", 3 * 1000},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			clock := newManualClock()
+			throttled := false
+
+			bc := NewTestnetClient("test-api-key", "test-secret-key")
+			bc.weightController = newWeightControllerWithClock(clock.Now)
+			bc.SetHTTPClient(doerFunc(func(request *http.Request) (*http.Response, error) {
+				if !throttled {
+					return jsonResponse(request, `{"serverTime":1499827319559}`), nil
+				}
+
+				header := http.Header{}
+				if testCase.retryAfter != "" {
+					header.Set("Retry-After", testCase.retryAfter)
+				}
+
+				return &http.Response{
+					StatusCode: http.StatusTooManyRequests,
+					Header:     header,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"code":-1003,"msg":"Too many requests."}`)),
+					Request:    request,
+				}, nil
+			}))
+
+			if _, warning, err := bc.GetServerTime(); err != nil || warning != nil {
+				t.Fatalf("unexpected warning %v or error %v", warning, err)
+			}
+
+			clock.Advance(20 * time.Second)
+			throttled = true
+
+			_, warning, err := bc.GetServerTime()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if warning == nil || !errors.Is(warning, ErrRateLimited) {
+				t.Fatalf("expected rate limit warning, got %v", warning)
+			}
+
+			if retryAfterMS := warning.GetRetryAfterTimeMS(); retryAfterMS != testCase.expectedRetryMS {
+				t.Fatalf("expected retry after %dms, got %dms", testCase.expectedRetryMS, retryAfterMS)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfterMS(t *testing.T) {
+	testCases := map[string]int64{
+		"":        0,
+		"0":       0,
+		"-5":      0,
+		"garbage": 0,
+		"7":       7000,
+		" 12 ":    12000,
+	}
+
+	for retryAfter, expectedMS := range testCases {
+		if actualMS := parseRetryAfterMS(retryAfter); actualMS != expectedMS {
+			t.Errorf("%q: expected %dms, got %dms", retryAfter, expectedMS, actualMS)
+		}
+	}
+
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if actualMS := parseRetryAfterMS(future); actualMS <= 0 || actualMS > 60*1000 {
+		t.Errorf("HTTP date: expected delay within a minute, got %dms", actualMS)
+	}
+}
@@ -0,0 +1,69 @@
+package bncclient
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+)
+
+const testnetStreamBaseURL = "wss://stream.testnet.binance.vision"
+const defaultRecvWindowMS = 5000 // Binance default: request is valid for 5000ms after timestamp
+
+// NewSignedBinanceClient - creates client which can call SIGNED endpoints (trading, account data).
+// Secret key is used only locally to sign requests (HMAC SHA256) and is never sent to Binance.
+func NewSignedBinanceClient(apiKey string, secretKey string) *BinanceClient {
+	bc := NewBinanceClient(apiKey)
+	bc.SetSecretKey(secretKey)
+
+	return bc
+}
+
+// NewTestnetClient - creates signed client for Spot Test Network (https://testnet.binance.vision), which is a safe sandbox
+// to test order placement before going live. Testnet has its own API keys (generated on testnet site), they don't work
+// with production API, and vice versa. Signing scheme is the same as in production.
+func NewTestnetClient(apiKey string, secretKey string) *BinanceClient {
+	bc := NewSignedBinanceClient(apiKey, secretKey)
+	bc.baseURL = url.URL{Scheme: "https", Host: "testnet.binance.vision"}
+	bc.streamBaseURL = testnetStreamBaseURL
+	bc.weightController = newWeightController() // Testnet limits are separate from production ones
+	bc.sapiWeightController = newWeightController()
+	bc.sapiWeightController.setLimits(sapiWeightLimitPerMinute, sessionDurationMS)
+
+	return bc
+}
+
+// SetSecretKey - sets secret key used to sign requests to SIGNED endpoints.
+func (bc *BinanceClient) SetSecretKey(secretKey string) {
+	bc.secretKey = secretKey
+}
+
+// SetRecvWindow - sets how long (ms) after timestamp the signed request is valid. Binance default is 5000, maximum is 60000.
+func (bc *BinanceClient) SetRecvWindow(recvWindowMS int64) {
+	bc.recvWindowMS = recvWindowMS
+}
+
+// signQuery adds timestamp and recvWindow to url-encoded query string, and appends signature of the whole string as the last parameter.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#signed-trade-and-user_data-endpoint-security
+func (bc *BinanceClient) signQuery(rawQuery string) string {
+	recvWindowMS := bc.recvWindowMS
+	if recvWindowMS <= 0 {
+		recvWindowMS = defaultRecvWindowMS
+	}
+
+	if rawQuery != "" {
+		rawQuery += "&"
+	}
+	rawQuery += "recvWindow=" + strconv.FormatInt(recvWindowMS, 10) + "&timestamp=" + strconv.FormatInt(bc.requestTimestampMS(), 10)
+
+	mac := hmac.New(sha256.New, []byte(bc.secretKey))
+	mac.Write([]byte(rawQuery))
+
+	return rawQuery + "&signature=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// requestTimestampMS returns timestamp for signed request: local time corrected by offset measured with SyncTime (if any).
+func (bc *BinanceClient) requestTimestampMS() int64 {
+	return bc.EstimatedServerTimeMS()
+}
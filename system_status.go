@@ -0,0 +1,106 @@
+package bncclient
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const maintenanceRecheckIntervalMS = 60 * 1000 // How often system status is re-checked while Binance is in maintenance
+
+// SystemStatus -- status of Binance system: 0 - normal, 1 - system maintenance.
+type SystemStatus struct {
+	Status int    `json:"status"`
+	Msg    string `json:"msg"`
+}
+
+// maintenanceDetector -- state of maintenance detection (see SetMaintenanceDetection).
+type maintenanceDetector struct {
+	enabled       bool
+	inMaintenance bool
+	checkedAtMS   int64
+	mutex         sync.Mutex
+}
+
+// GetSystemStatus - fetches Binance system status.
+// Details: https://binance-docs.github.io/apidocs/spot/en/#system-status-system
+func (bc *BinanceClient) GetSystemStatus() (SystemStatus, Warning, error) {
+	var systemStatus SystemStatus
+
+	warning, err := bc.newRequest(http.MethodGet).Do("/sapi/v1/system/status", 1, &systemStatus)
+
+	if err != nil || warning != nil {
+		return SystemStatus{}, warning, err
+	}
+
+	return systemStatus, nil, nil
+}
+
+// IsMaintenance returns true if Binance is in maintenance.
+func (s SystemStatus) IsMaintenance() bool {
+	return s.Status == 1
+}
+
+// SetMaintenanceDetection - when enabled, server errors (5xx) of market data requests trigger check of system status,
+// and if Binance is in maintenance, ErrSystemMaintenance is returned instead of Warning. While maintenance lasts,
+// market data requests fail with ErrSystemMaintenance right away, without sending (system status is re-checked
+// once a minute). This gives bots a clean signal to pause instead of retrying blindly. Disabled by default.
+func (bc *BinanceClient) SetMaintenanceDetection(enabled bool) {
+	bc.maintenance.mutex.Lock()
+	defer bc.maintenance.mutex.Unlock()
+
+	bc.maintenance.enabled = enabled
+	bc.maintenance.inMaintenance = false
+}
+
+// isMarketDataRequest -- public GET request to /api endpoints.
+func isMarketDataRequest(method string, path string, signed bool) bool {
+	return method == http.MethodGet && !signed && strings.HasPrefix(path, "/api/")
+}
+
+// checkMaintenanceBeforeRequest returns ErrSystemMaintenance, if Binance is known to be in maintenance.
+func (bc *BinanceClient) checkMaintenanceBeforeRequest() error {
+	bc.maintenance.mutex.Lock()
+	enabled, inMaintenance := bc.maintenance.enabled, bc.maintenance.inMaintenance
+	recheckDue := currentTimestampMS()-bc.maintenance.checkedAtMS >= maintenanceRecheckIntervalMS
+	bc.maintenance.mutex.Unlock()
+
+	if !enabled || !inMaintenance {
+		return nil
+	}
+
+	if recheckDue {
+		return bc.detectMaintenance()
+	}
+
+	return ErrSystemMaintenance
+}
+
+// detectMaintenance checks system status and returns ErrSystemMaintenance if Binance is in maintenance.
+// If status can't be checked, nothing is changed and nil is returned.
+func (bc *BinanceClient) detectMaintenance() error {
+	systemStatus, warning, err := bc.GetSystemStatus()
+
+	if err != nil || warning != nil {
+		return nil
+	}
+
+	bc.maintenance.mutex.Lock()
+	defer bc.maintenance.mutex.Unlock()
+
+	bc.maintenance.inMaintenance = systemStatus.IsMaintenance()
+	bc.maintenance.checkedAtMS = currentTimestampMS()
+
+	if bc.maintenance.inMaintenance {
+		return ErrSystemMaintenance
+	}
+
+	return nil
+}
+
+func (bc *BinanceClient) maintenanceDetectionEnabled() bool {
+	bc.maintenance.mutex.Lock()
+	defer bc.maintenance.mutex.Unlock()
+
+	return bc.maintenance.enabled
+}
@@ -0,0 +1,51 @@
+package bncclient_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/anxp/bncclient"
+)
+
+// Integration test against Binance Spot Testnet (https://testnet.binance.vision). Skipped unless testnet keys are set
+// in BNC_TESTNET_API_KEY and BNC_TESTNET_SECRET_KEY environment variables.
+func TestTestnetSignedRequests(t *testing.T) {
+	apiKey, secretKey := os.Getenv("BNC_TESTNET_API_KEY"), os.Getenv("BNC_TESTNET_SECRET_KEY")
+	if apiKey == "" || secretKey == "" {
+		t.Skip("BNC_TESTNET_API_KEY and BNC_TESTNET_SECRET_KEY are not set")
+	}
+
+	if testing.Short() {
+		t.Skip("integration test is skipped in short mode")
+	}
+
+	const symbol = "BTCUSDT"
+	client := bncclient.NewTestnetClient(apiKey, secretKey)
+
+	if _, warning, err := client.GetServerTime(); err != nil || warning != nil {
+		t.Fatalf("GetServerTime: unexpected warning %v or error %v", warning, err)
+	}
+
+	exchangeInfo, warning, err := client.GetExchangeInfo()
+	if err != nil || warning != nil {
+		t.Fatalf("GetExchangeInfo: unexpected warning %v or error %v", warning, err)
+	}
+
+	if _, exists := exchangeInfo.GetSymbolInfo(symbol); !exists {
+		t.Skipf("%s is not listed on testnet", symbol)
+	}
+
+	// Signed request: with no open orders, empty list is returned.
+	if _, warning, err := client.CancelAllOpenOrders(symbol); err != nil || warning != nil {
+		t.Fatalf("CancelAllOpenOrders: unexpected warning %v or error %v", warning, err)
+	}
+
+	listenKey, err := client.CreateListenKey()
+	if err != nil || listenKey == "" {
+		t.Fatalf("CreateListenKey: unexpected listen key %q or error %v", listenKey, err)
+	}
+
+	if err := client.CloseListenKey(listenKey); err != nil {
+		t.Fatalf("CloseListenKey: unexpected error %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+// Package testutil provides helpers to test code which uses bncclient without network access.
+package testutil
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/anxp/bncclient"
+)
+
+// CannedResponse is a response which CannedDoer returns for a request.
+type CannedResponse struct {
+	StatusCode int
+	Body       string
+	Header     http.Header
+}
+
+// CannedDoer implements bncclient.Doer and returns canned responses keyed by request path (like "/api/v3/depth").
+// All performed requests are recorded and can be inspected with Requests().
+type CannedDoer struct {
+	responses map[string]CannedResponse
+	requests  []*http.Request
+	mutex     sync.Mutex
+}
+
+// NewCannedDoer creates CannedDoer with given responses, keyed by request path.
+func NewCannedDoer(responses map[string]CannedResponse) *CannedDoer {
+	cannedResponses := make(map[string]CannedResponse, len(responses))
+	for path, response := range responses {
+		cannedResponses[path] = response
+	}
+
+	return &CannedDoer{responses: cannedResponses}
+}
+
+// SetResponse adds or replaces canned response for given path.
+func (d *CannedDoer) SetResponse(path string, response CannedResponse) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.responses[path] = response
+}
+
+// Requests returns all requests performed so far.
+func (d *CannedDoer) Requests() []*http.Request {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	requests := make([]*http.Request, len(d.requests))
+	copy(requests, d.requests)
+
+	return requests
+}
+
+// Do returns canned response for request path. If there is no response for the path, Binance-like 404 is returned.
+func (d *CannedDoer) Do(request *http.Request) (*http.Response, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.requests = append(d.requests, request)
+
+	cannedResponse, exists := d.responses[request.URL.Path]
+	if !exists {
+		cannedResponse = CannedResponse{StatusCode: 404, Body: `{"code":-1,"msg":"No canned response for this path"}`}
+	}
+
+	if cannedResponse.StatusCode == 0 {
+		cannedResponse.StatusCode = 200
+	}
+
+	header := http.Header{}
+	for key, values := range cannedResponse.Header {
+		header[key] = append([]string(nil), values...)
+	}
+
+	return &http.Response{
+		StatusCode: cannedResponse.StatusCode,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewBufferString(cannedResponse.Body)),
+		Request:    request,
+	}, nil
+}
+
+// NewClient returns BinanceClient backed by canned JSON responses (with status 200), keyed by request path.
+// Returned CannedDoer can be used to add more responses or to inspect performed requests.
+func NewClient(jsonResponses map[string]string) (*bncclient.BinanceClient, *CannedDoer) {
+	responses := make(map[string]CannedResponse, len(jsonResponses))
+	for path, body := range jsonResponses {
+		responses[path] = CannedResponse{StatusCode: 200, Body: body}
+	}
+
+	doer := NewCannedDoer(responses)
+	client := bncclient.NewBinanceClient("test-api-key")
+	client.SetHTTPClient(doer)
+
+	return client, doer
+}
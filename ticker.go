@@ -0,0 +1,148 @@
+package bncclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+var rollingWindowSizeRegexp = regexp.MustCompile(`^([1-9][0-9]?)([mhd])$`)
+
+// rollingWindowSizeMaxValues -- maximum allowed value of windowSize for every unit: 1m-59m, 1h-23h, 1d-7d.
+var rollingWindowSizeMaxValues = map[string]int{
+	"m": 59,
+	"h": 23,
+	"d": 7,
+}
+
+// TickerStats -- price change statistics, common for all ticker endpoints (rolling window and 24hr).
+type TickerStats struct {
+	Symbol             string  `json:"symbol"`
+	PriceChange        float64 `json:"priceChange,string"`
+	PriceChangePercent float64 `json:"priceChangePercent,string"`
+	WeightedAvgPrice   float64 `json:"weightedAvgPrice,string"`
+	OpenPrice          float64 `json:"openPrice,string"`
+	HighPrice          float64 `json:"highPrice,string"`
+	LowPrice           float64 `json:"lowPrice,string"`
+	LastPrice          float64 `json:"lastPrice,string"`
+	Volume             float64 `json:"volume,string"`
+	QuoteVolume        float64 `json:"quoteVolume,string"`
+	OpenTime           int64   `json:"openTime"`
+	CloseTime          int64   `json:"closeTime"`
+	FirstId            int64   `json:"firstId"`
+	LastId             int64   `json:"lastId"`
+	Count              int64   `json:"count"`
+}
+
+type RollingTicker struct {
+	TickerStats
+}
+
+// GetRollingWindowTicker - Price change statistics within a requested window, which is defined by windowSize.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#rolling-window-price-change-statistics
+// Allowed windowSize values: 1m-59m, 1h-23h, 1d-7d (for example "15m", "4h", "1d").
+func (bc *BinanceClient) GetRollingWindowTicker(symbol string, windowSize string) (RollingTicker, Warning, error) {
+	if err := validateRollingWindowSize(windowSize); err != nil {
+		return RollingTicker{}, nil, err
+	}
+
+	var rollingTicker RollingTicker
+
+	warning, err := bc.newRequest(http.MethodGet).
+		AddString("symbol", symbol).
+		AddString("windowSize", windowSize).
+		Do("/api/v3/ticker", 4, &rollingTicker)
+
+	if err != nil || warning != nil {
+		return RollingTicker{}, warning, err
+	}
+
+	return rollingTicker, nil, nil
+}
+
+// validateRollingWindowSize checks windowSize format: number and unit (m, h, d) within allowed range.
+func validateRollingWindowSize(windowSize string) error {
+	matches := rollingWindowSizeRegexp.FindStringSubmatch(windowSize)
+
+	if matches == nil {
+		return errors.New(fmt.Sprintf("Not allowed windowSize: %s", windowSize))
+	}
+
+	value, _ := strconv.Atoi(matches[1])
+	if value > rollingWindowSizeMaxValues[matches[2]] {
+		return errors.New(fmt.Sprintf("Not allowed windowSize: %s (maximum for this unit is %d)", windowSize, rollingWindowSizeMaxValues[matches[2]]))
+	}
+
+	return nil
+}
+
+// BookTicker -- best price and quantity on the order book for a symbol.
+type BookTicker struct {
+	Symbol   string  `json:"symbol"`
+	BidPrice float64 `json:"bidPrice,string"`
+	BidQty   float64 `json:"bidQty,string"`
+	AskPrice float64 `json:"askPrice,string"`
+	AskQty   float64 `json:"askQty,string"`
+}
+
+// GetAllBookTickers - best bid/ask for all symbols of the market in one request, keyed by symbol for O(1) lookup.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#symbol-order-book-ticker
+// Much cheaper than per-symbol requests, when many symbols are scanned.
+func (bc *BinanceClient) GetAllBookTickers() (map[string]BookTicker, Warning, error) {
+	bookTickersRaw, _, _, warning, err := bc.newRequest(http.MethodGet).DoRaw("/api/v3/ticker/bookTicker", 4)
+
+	if err != nil || warning != nil {
+		return nil, warning, err
+	}
+
+	trimmedResponse := bytes.TrimSpace(bookTickersRaw)
+	if len(trimmedResponse) == 0 || trimmedResponse[0] != '[' {
+		var unexpected []BookTicker
+		return nil, nil, bc.tryParseArrayResponse(bookTickersRaw, &unexpected) // Reports Binance error or unexpected shape
+	}
+
+	// Array is decoded element by element straight into the map, without intermediate slice:
+	decoder := json.NewDecoder(bytes.NewReader(trimmedResponse))
+	if _, err := decoder.Token(); err != nil { // Opening "["
+		return nil, nil, fmt.Errorf("failed to parse Binance response: %w", err)
+	}
+
+	bookTickers := make(map[string]BookTicker)
+
+	for decoder.More() {
+		var bookTicker BookTicker
+		if err := decoder.Decode(&bookTicker); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse Binance response: %w", err)
+		}
+		bookTickers[bookTicker.Symbol] = bookTicker
+	}
+
+	return bookTickers, nil, nil
+}
+
+// AvgPrice -- current average price of the symbol over the last Mins minutes.
+type AvgPrice struct {
+	Mins      int     `json:"mins"`
+	Price     float64 `json:"price,string"`
+	CloseTime int64   `json:"closeTime"` // Time of the last trade. Added by Binance recently, 0 if absent in response
+}
+
+// GetAvgPrice - Current average price for a symbol.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#current-average-price
+func (bc *BinanceClient) GetAvgPrice(symbol string) (AvgPrice, Warning, error) {
+	var avgPrice AvgPrice
+
+	warning, err := bc.newRequest(http.MethodGet).
+		AddString("symbol", symbol).
+		Do("/api/v3/avgPrice", 2, &avgPrice)
+
+	if err != nil || warning != nil {
+		return AvgPrice{}, warning, err
+	}
+
+	return avgPrice, nil, nil
+}
@@ -0,0 +1,142 @@
+package bncclient
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const timeSyncMaxAttempts = 3
+const timeSyncInitialBackoffMS = 250               // Doubled after every failed attempt
+const DefaultTimeResyncInterval = 30 * time.Minute // Recommended interval of SetAutoTimeSync
+
+// timeSync -- offset between Binance server clock and local clock, measured by SyncTime.
+type timeSync struct {
+	offsetMS        int64 // serverTime - localTime
+	synced          bool
+	lastTimestampMS int64         // The latest estimated server time returned, estimates never go back in time
+	stopResync      chan struct{} // Stops background resync started by SetAutoTimeSync (nil if it's not running)
+	mutex           sync.Mutex
+}
+
+// SyncTime - measures offset between Binance server clock and local clock. The offset is then added to timestamp of every
+// signed request, so requests are not rejected (-1021 "Timestamp for this request is outside of the recvWindow")
+// when local clock drifts. Offset is measured as serverTime minus the middle of request's round trip.
+// When GetServerTime returns a Warning (throttled, server error), SyncTime retries up to 3 attempts with short backoff
+// (250ms, 500ms), and returns the last Warning only after all attempts failed. Errors are returned immediately.
+// On failure previously measured offset (if any) is kept, so a transient failure doesn't break signed requests.
+// If the client is closed during backoff, ErrClientClosed is returned right away.
+func (bc *BinanceClient) SyncTime() (Warning, error) {
+	var lastWarning Warning
+	backoffMS := int64(timeSyncInitialBackoffMS)
+
+	ctx, cancel := bc.lifecycle.bindContext(context.Background())
+	defer cancel()
+
+	for attempt := 1; attempt <= timeSyncMaxAttempts; attempt++ {
+		requestStartMS := currentTimestampMS()
+		serverTimeMS, warning, err := bc.GetServerTime()
+		requestEndMS := currentTimestampMS()
+		warning, err = splitWarning(warning, err)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if warning == nil {
+			bc.timeSync.setOffset(serverTimeMS - (requestStartMS+requestEndMS)/2)
+			return nil, nil
+		}
+
+		lastWarning = warning
+
+		if attempt < timeSyncMaxAttempts {
+			if err := sleepWithContext(ctx, backoffMS); err != nil {
+				return nil, ErrClientClosed
+			}
+			backoffMS *= 2
+		}
+	}
+
+	if bc.warningsAsErrors {
+		return nil, lastWarning
+	}
+
+	return lastWarning, nil
+}
+
+// GetTimeOffset - returns offset (ms) between server and local clocks measured by the last successful SyncTime,
+// and false if time was never synced.
+func (bc *BinanceClient) GetTimeOffset() (int64, bool) {
+	bc.timeSync.mutex.Lock()
+	defer bc.timeSync.mutex.Unlock()
+
+	return bc.timeSync.offsetMS, bc.timeSync.synced
+}
+
+// EstimatedServerTimeMS - returns current Binance server time estimated without network round trip: local time
+// corrected by offset measured with SyncTime (just local time, if time was never synced). Estimates never go back
+// in time, even if re-sync decreases the offset. The same value is used as timestamp of signed requests.
+func (bc *BinanceClient) EstimatedServerTimeMS() int64 {
+	return bc.timeSync.estimateServerTimeMS(currentTimestampMS())
+}
+
+// SetAutoTimeSync - starts re-syncing time (see SyncTime) in background every interval (DefaultTimeResyncInterval
+// is recommended), so the offset follows local clock drift. The first sync is done immediately. Failed attempts are
+// ignored (the previous offset is kept). Zero interval stops background re-sync. Re-sync is stopped by Close() too.
+func (bc *BinanceClient) SetAutoTimeSync(interval time.Duration) {
+	ts := bc.timeSync
+
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	if ts.stopResync != nil {
+		close(ts.stopResync)
+		ts.stopResync = nil
+	}
+
+	if interval <= 0 {
+		return
+	}
+
+	stop := make(chan struct{})
+	ts.stopResync = stop
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			_, _ = bc.SyncTime()
+
+			select {
+			case <-stop:
+				return
+			case <-bc.lifecycle.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (ts *timeSync) estimateServerTimeMS(localTimeMS int64) int64 {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	estimateMS := localTimeMS + ts.offsetMS
+	if estimateMS < ts.lastTimestampMS {
+		estimateMS = ts.lastTimestampMS
+	}
+	ts.lastTimestampMS = estimateMS
+
+	return estimateMS
+}
+
+func (ts *timeSync) setOffset(offsetMS int64) {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	ts.offsetMS = offsetMS
+	ts.synced = true
+}
@@ -0,0 +1,49 @@
+package bncclient
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TimeUnit -- resolution of time and timestamp fields in Binance responses.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#general-api-information
+type TimeUnit string
+
+const (
+	TimeUnitMillisecond TimeUnit = "MILLISECOND" // Binance default
+	TimeUnitMicrosecond TimeUnit = "MICROSECOND"
+)
+
+// SetTimeUnit - sets resolution of time fields in responses (X-MBX-TIME-UNIT header). Default is TimeUnitMillisecond.
+// With TimeUnitMicrosecond, raw time fields of returned structures (like OneTrade.Time, AggTrade.AggTime, Kline.OpenTime)
+// contain microseconds since epoch, use TimestampToTime to convert them regardless of the unit.
+// Methods documented as returning milliseconds (like GetServerTime) keep returning milliseconds.
+// Time parameters of requests (startTime, endTime) are always in milliseconds.
+func (bc *BinanceClient) SetTimeUnit(unit TimeUnit) error {
+	if unit != TimeUnitMillisecond && unit != TimeUnitMicrosecond {
+		return errors.New(fmt.Sprintf("Not allowed time unit: %s", unit))
+	}
+
+	bc.timeUnit = unit
+
+	return nil
+}
+
+// TimestampToTime - converts raw time field of Binance response to time.Time (in UTC), according to the client's time unit.
+func (bc *BinanceClient) TimestampToTime(timestamp int64) time.Time {
+	if bc.timeUnit == TimeUnitMicrosecond {
+		return time.Unix(0, timestamp*int64(time.Microsecond)).UTC()
+	}
+
+	return msToTime(timestamp)
+}
+
+// timestampToMS converts raw time field of Binance response to milliseconds, according to the client's time unit.
+func (bc *BinanceClient) timestampToMS(timestamp int64) int64 {
+	if bc.timeUnit == TimeUnitMicrosecond {
+		return timestamp / 1000
+	}
+
+	return timestamp
+}
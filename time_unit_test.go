@@ -0,0 +1,70 @@
+package bncclient
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTimeUnitSameLogicalTime(t *testing.T) {
+	const tradeTimeMS = int64(1499865549590)
+	const rangeEndMS = tradeTimeMS + 10
+
+	testCases := []struct {
+		unit       TimeUnit
+		multiplier int64
+	}{
+		{TimeUnitMillisecond, 1},
+		{TimeUnitMicrosecond, 1000},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.unit), func(t *testing.T) {
+			var timeUnitHeader string
+
+			// The second trade is 1ms after the end of requested range:
+			body := fmt.Sprintf(`[{"a":1,"p":"1.0","q":"1.0","f":1,"l":1,"T":%d,"m":true},{"a":2,"p":"1.0","q":"1.0","f":2,"l":2,"T":%d,"m":true}]`,
+				tradeTimeMS*testCase.multiplier, (rangeEndMS+1)*testCase.multiplier)
+
+			bc := newUnlimitedClient(doerFunc(func(request *http.Request) (*http.Response, error) {
+				timeUnitHeader = request.Header.Get("X-MBX-TIME-UNIT")
+				return jsonResponse(request, body), nil
+			}))
+			if err := bc.SetTimeUnit(testCase.unit); err != nil {
+				t.Fatal(err)
+			}
+
+			aggTrades, warning, err := bc.GetAggregatedTradesByTimeRange("BTCUSDT", tradeTimeMS-10, rangeEndMS)
+			if err != nil || warning != nil {
+				t.Fatalf("unexpected warning %v or error %v", warning, err)
+			}
+
+			if timeUnitHeader != string(testCase.unit) {
+				t.Errorf("expected X-MBX-TIME-UNIT %s, got %q", testCase.unit, timeUnitHeader)
+			}
+
+			if len(aggTrades) != 1 || aggTrades[0].AggTradeId != 1 {
+				t.Fatalf("expected only the trade within the range, got %+v", aggTrades)
+			}
+
+			expectedTime := time.Unix(0, tradeTimeMS*int64(time.Millisecond)).UTC()
+			if actual := bc.TimestampToTime(aggTrades[0].AggTime); !actual.Equal(expectedTime) {
+				t.Errorf("expected %v, got %v", expectedTime, actual)
+			}
+
+			series := aggTrades.Series().WithTimeUnit(testCase.unit)
+			if inRange := series.InRange(tradeTimeMS, tradeTimeMS); len(inRange) != 1 {
+				t.Errorf("expected the trade to be found by its time in milliseconds, got %+v", inRange)
+			}
+		})
+	}
+}
+
+func TestSetTimeUnitRejectsUnknownUnit(t *testing.T) {
+	bc := NewBinanceClient("test-api-key")
+
+	if err := bc.SetTimeUnit("NANOSECOND"); err == nil {
+		t.Fatal("expected error for unknown time unit")
+	}
+}
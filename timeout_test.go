@@ -0,0 +1,51 @@
+package bncclient
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// routeToServer makes default HTTP client of the client send all requests to the test server, whatever host they are for.
+func routeToServer(bc *BinanceClient, server *httptest.Server) {
+	bc.transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} // Certificate of the test server is not for Binance host
+	bc.transport.DialContext = func(ctx context.Context, network string, addr string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+}
+
+func TestSetTimeoutSlowServer(t *testing.T) {
+	releaseServer := make(chan struct{})
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-releaseServer:
+		case <-time.After(5 * time.Second):
+		}
+		_, _ = w.Write([]byte(`{"serverTime":1499827319559}`))
+	}))
+	defer server.Close()
+	defer close(releaseServer) // Deferred calls run in reverse order, so the handler is released before server.Close()
+
+	bc := NewBinanceClient("test-api-key")
+	bc.weightController = emptyWeightController()
+	routeToServer(bc, server)
+
+	const timeout = 100 * time.Millisecond
+	bc.SetTimeout(timeout)
+
+	startedAt := time.Now()
+	_, warning, err := bc.GetServerTime()
+	elapsed := time.Since(startedAt)
+
+	if err == nil && warning == nil {
+		t.Fatal("expected the call to fail with timeout")
+	}
+
+	if elapsed > timeout+time.Second {
+		t.Fatalf("expected the call to return within the timeout bound, took %v", elapsed)
+	}
+}
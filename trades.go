@@ -0,0 +1,285 @@
+package bncclient
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const tradesMaxLimit = 1000 // Binance returns maximum 1000 trades per request
+
+// TradesOptions -- options of GetTrades. Zero value means the most recent trades with default limit.
+type TradesOptions struct {
+	Limit      int    // Allowed values: [1, 1000]. 0 or -1 means default limit (500).
+	FromId     *int64 // Trade id to fetch from. If set, historical trades endpoint is used.
+	Historical bool   // Use historical trades endpoint even without FromId (it's heavier: weight 5 instead of 1).
+}
+
+// GetTrades - unified entry point for recent and historical trades. Without FromId (and Historical flag) recent trades
+// endpoint is used, otherwise - historical trades endpoint. Both return the same TradesList.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#recent-trades-list
+// and https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#old-trade-lookup-market_data
+func (bc *BinanceClient) GetTrades(symbol string, opts TradesOptions) (TradesList, Warning, error) {
+	limit := opts.Limit
+	if limit == 0 {
+		limit = -1
+	}
+
+	if err := validateLimit(limit, tradesMaxLimit); err != nil {
+		return nil, nil, err
+	}
+
+	path, weight := "/api/v3/trades", 1
+	if opts.Historical || opts.FromId != nil {
+		path, weight = "/api/v3/historicalTrades", 5
+	}
+
+	var trades TradesList
+
+	warning, err := bc.newRequest(http.MethodGet).
+		AddString("symbol", symbol).
+		AddInt("limit", limit, true).
+		AddOptionalInt64("fromId", opts.FromId).
+		DoArray(path, weight, &trades)
+
+	if err != nil || warning != nil {
+		return nil, warning, err
+	}
+
+	return trades, nil, nil
+}
+
+// IterateHistoricalTrades - returns iterator which walks forward through historical trades, starting from startFromId.
+// Every call of iterator fetches next page (up to 1000 trades), next page starts from the last returned trade's Id + 1.
+// Iteration stops after the trade with stopAtId (set stopAtId to -1 to walk up to the most recent trade),
+// or when page is shorter than limit. After that iterator returns ErrNoMoreTrades.
+// When iterator returns a Warning, the caller should sleep recommended time and call iterator again - the same page will be requested.
+func (bc *BinanceClient) IterateHistoricalTrades(symbol string, startFromId int64, stopAtId int64) func() (TradesList, Warning, error) {
+	nextFromId := startFromId
+	exhausted := false
+
+	return func() (TradesList, Warning, error) {
+		if exhausted {
+			return nil, nil, ErrNoMoreTrades
+		}
+
+		trades, warning, err := bc.GetHistoricalTrades(symbol, tradesMaxLimit, nextFromId)
+
+		if err != nil || warning != nil {
+			return nil, warning, err
+		}
+
+		if len(trades) < tradesMaxLimit {
+			exhausted = true
+		}
+
+		if stopAtId >= 0 {
+			for i, trade := range trades {
+				if trade.Id >= stopAtId {
+					exhausted = true
+					trades = trades[:i+1]
+					if trade.Id > stopAtId {
+						trades = trades[:i]
+					}
+					break
+				}
+			}
+		}
+
+		if len(trades) == 0 {
+			exhausted = true
+			return nil, nil, ErrNoMoreTrades
+		}
+
+		nextFromId = trades[len(trades)-1].Id + 1
+
+		return trades, nil, nil
+	}
+}
+
+// Trade -- common interface of individual (OneTrade) and aggregated (AggTrade) trades, so downstream code can process
+// either kind uniformly. Note that ids of individual and aggregated trades are from different id spaces, so ids
+// are not part of the interface. GetTime returns raw timestamp (milliseconds, unless changed by SetTimeUnit).
+type Trade interface {
+	GetPrice() float64
+	GetQty() float64
+	GetTime() int64
+	GetIsBuyerMaker() bool
+}
+
+func (t OneTrade) GetPrice() float64 {
+	return t.Price
+}
+
+func (t OneTrade) GetQty() float64 {
+	return t.Qty
+}
+
+func (t OneTrade) GetTime() int64 {
+	return t.Time
+}
+
+func (t OneTrade) GetIsBuyerMaker() bool {
+	return t.IsBuyerMaker
+}
+
+func (t AggTrade) GetPrice() float64 {
+	return t.AggPrice
+}
+
+func (t AggTrade) GetQty() float64 {
+	return t.AggQty
+}
+
+func (t AggTrade) GetTime() int64 {
+	return t.AggTime
+}
+
+func (t AggTrade) GetIsBuyerMaker() bool {
+	return t.AggIsBuyerMaker
+}
+
+// AsTrades converts list of individual trades to list of Trade interface values.
+func (tl TradesList) AsTrades() []Trade {
+	trades := make([]Trade, len(tl))
+	for i, trade := range tl {
+		trades[i] = trade
+	}
+
+	return trades
+}
+
+// AsTrades converts list of aggregated trades to list of Trade interface values.
+func (atl AggTradesList) AsTrades() []Trade {
+	trades := make([]Trade, len(atl))
+	for i, aggTrade := range atl {
+		trades[i] = aggTrade
+	}
+
+	return trades
+}
+
+// VWAP - volume-weighted average price of the trades. Returns 0 for empty list (or list with zero total quantity).
+func (tl TradesList) VWAP() float64 {
+	return vwap(tl.AsTrades())
+}
+
+// VWAP - volume-weighted average price of the aggregated trades. Returns 0 for empty list (or list with zero total quantity).
+func (atl AggTradesList) VWAP() float64 {
+	return vwap(atl.AsTrades())
+}
+
+func vwap(trades []Trade) float64 {
+	notional, volume := 0.0, 0.0
+
+	for _, trade := range trades {
+		notional += trade.GetPrice() * trade.GetQty()
+		volume += trade.GetQty()
+	}
+
+	if volume == 0 {
+		return 0
+	}
+
+	return notional / volume
+}
+
+// GetRecentTape - gets recent individual (not aggregated) trades as list of Trade, ready to be processed together with
+// aggregated trades (see AggTradesList.AsTrades). Limit is optional, set it to -1 if you don't want to specify it.
+// Allowed values for limit: [1, 1000], otherwise ErrInvalidLimit is returned.
+func (bc *BinanceClient) GetRecentTape(symbol string, limit int) ([]Trade, Warning, error) {
+	trades, warning, err := bc.GetRecentTrades(symbol, limit)
+
+	if err != nil || warning != nil {
+		return nil, warning, err
+	}
+
+	return trades.AsTrades(), nil, nil
+}
+
+// FollowRecentTrades - REST-based live tape: polls GetRecentTrades every interval and emits every new individual trade
+// (with Id greater than the last seen one) to returned channel, in ascending order. It's an alternative to trade streams
+// for environments where WebSocket connections can't be opened. Trades which already existed before the first poll are
+// not emitted (see FollowRecentTradesWithBacklog). If interval is not positive, trades are polled every second.
+// Every poll requests up to 1000 trades, so if more trades happen between two polls, the oldest of them are missed -
+// choose interval according to the symbol's activity.
+// Trades channel is unbuffered, so slow consumer naturally slows down polling (backpressure).
+// When weight controller returns a Warning, it sleeps recommended time and continues.
+// Any error is sent to errors channel and stops following. Both channels are closed when it stops (ctx cancelled,
+// client closed or error).
+func (bc *BinanceClient) FollowRecentTrades(ctx context.Context, symbol string, interval time.Duration) (<-chan OneTrade, <-chan error) {
+	return bc.followRecentTrades(ctx, symbol, interval, false)
+}
+
+// FollowRecentTradesWithBacklog - the same as FollowRecentTrades, but the first poll's batch (up to 1000 most recent
+// trades) is emitted too.
+func (bc *BinanceClient) FollowRecentTradesWithBacklog(ctx context.Context, symbol string, interval time.Duration) (<-chan OneTrade, <-chan error) {
+	return bc.followRecentTrades(ctx, symbol, interval, true)
+}
+
+func (bc *BinanceClient) followRecentTrades(ctx context.Context, symbol string, interval time.Duration, emitFirstBatch bool) (<-chan OneTrade, <-chan error) {
+	tradesCh := make(chan OneTrade)
+	errCh := make(chan error, 1)
+	ctx, cancel := bc.lifecycle.bindContext(ctx)
+
+	if interval <= 0 {
+		interval = aggTradesStreamPollIntervalMS * time.Millisecond
+	}
+
+	go func() {
+		defer cancel()
+		defer close(tradesCh)
+		defer close(errCh)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		lastSeenId := int64(-1)
+		firstPoll := true
+
+		for ctx.Err() == nil {
+			trades, warning, err := bc.WithContext(ctx).GetRecentTrades(symbol, tradesMaxLimit)
+			warning, err = splitWarning(warning, err)
+
+			if err != nil {
+				if ctx.Err() == nil { // Cancellation is not an error of the stream
+					errCh <- err
+				}
+				return
+			}
+
+			if warning != nil {
+				if sleepWithContext(ctx, warning.GetRetryAfterTimeMS()) != nil {
+					return
+				}
+				continue
+			}
+
+			for _, trade := range trades {
+				if trade.Id <= lastSeenId {
+					continue // Already emitted in one of previous polls
+				}
+
+				if !firstPoll || emitFirstBatch {
+					select {
+					case tradesCh <- trade:
+					case <-ctx.Done():
+						return
+					}
+				}
+
+				lastSeenId = trade.Id
+			}
+
+			firstPoll = false
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return tradesCh, errCh
+}
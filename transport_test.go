@@ -0,0 +1,101 @@
+package bncclient
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newServerTimeServer -- server which answers /api/v3/time and counts new connections.
+func newServerTimeServer(connections *int64) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"serverTime":1499827319559}`))
+	}))
+
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(connections, 1)
+		}
+	}
+
+	server.Start()
+
+	return server
+}
+
+func newBenchmarkClient(b *testing.B, serverURL string) *BinanceClient {
+	bc := NewTestnetClient("test-api-key", "test-secret-key")
+	bc.weightController.setLimits(math.MaxInt32, sessionDurationMS) // Benchmark is not limited by weight
+
+	if err := bc.SetBaseURL(serverURL); err != nil {
+		b.Fatal(err)
+	}
+
+	return bc
+}
+
+// BenchmarkConnectionReuse compares shared tuned transport of the client with a new HTTP client per call
+// (see new-conns/op metric). Requests are made from 8 goroutines concurrently.
+func BenchmarkConnectionReuse(b *testing.B) {
+	const concurrency = 8
+
+	run := func(b *testing.B, bc *BinanceClient, perCallClient bool) {
+		var connections int64
+		server := newServerTimeServer(&connections)
+		defer server.Close()
+
+		if err := bc.SetBaseURL(server.URL); err != nil {
+			b.Fatal(err)
+		}
+
+		b.ResetTimer()
+
+		var wg sync.WaitGroup
+		requests := make(chan struct{})
+
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range requests {
+					client := bc
+					transport := newDefaultTransport()
+					if perCallClient {
+						copied := *bc
+						copied.httpClient = &http.Client{Transport: transport, Timeout: 10 * time.Second}
+						client = &copied
+					}
+
+					if _, warning, err := client.GetServerTime(); err != nil || warning != nil {
+						b.Errorf("unexpected warning %v or error %v", warning, err)
+					}
+
+					transport.CloseIdleConnections()
+				}
+			}()
+		}
+
+		for i := 0; i < b.N; i++ {
+			requests <- struct{}{}
+		}
+		close(requests)
+		wg.Wait()
+
+		b.ReportMetric(float64(atomic.LoadInt64(&connections))/float64(b.N), "new-conns/op")
+	}
+
+	b.Run("shared-transport", func(b *testing.B) {
+		bc := newBenchmarkClient(b, "http://127.0.0.1")
+		bc.SetTransportOptions(100, concurrency, 90*time.Second)
+		run(b, bc, false)
+	})
+
+	b.Run("client-per-call", func(b *testing.B) {
+		run(b, newBenchmarkClient(b, "http://127.0.0.1"), true)
+	})
+}
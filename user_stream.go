@@ -0,0 +1,322 @@
+package bncclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const defaultStreamBaseURL = "wss://stream.binance.com:9443"
+const listenKeyKeepAliveInterval = 30 * time.Minute // Listen key expires after 60 minutes without keepalive
+const userDataStreamChannelSize = 100
+
+// ExecutionReport -- "executionReport" event of user data stream, sent on every order update.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/user-data-stream.md#order-update
+// Note: Go's JSON decoder matches keys case-insensitively when there is no exact match, so keys which differ only
+// by case (like "i" and "I") must all be declared here, even unused ones.
+type ExecutionReport struct {
+	EventType                string  `json:"e"`
+	EventTime                int64   `json:"E"`
+	Symbol                   string  `json:"s"`
+	ClientOrderId            string  `json:"c"`
+	Side                     string  `json:"S"`
+	OrderType                string  `json:"o"`
+	TimeInForce              string  `json:"f"`
+	Quantity                 float64 `json:"q,string"`
+	Price                    float64 `json:"p,string"`
+	StopPrice                float64 `json:"P,string"`
+	IcebergQuantity          float64 `json:"F,string"`
+	OrderListId              int64   `json:"g"`
+	OrigClientOrderId        string  `json:"C"`
+	ExecutionType            string  `json:"x"`
+	OrderStatus              string  `json:"X"`
+	RejectReason             string  `json:"r"`
+	OrderId                  int64   `json:"i"`
+	LastExecutedQuantity     float64 `json:"l,string"`
+	CumulativeFilledQuantity float64 `json:"z,string"`
+	LastExecutedPrice        float64 `json:"L,string"`
+	CommissionAmount         float64 `json:"n,string"`
+	CommissionAsset          string  `json:"N"`
+	TransactionTime          int64   `json:"T"`
+	TradeId                  int64   `json:"t"`
+	Ignore                   int64   `json:"I"`
+	IsOnBook                 bool    `json:"w"`
+	IsMaker                  bool    `json:"m"`
+	IgnoreM                  bool    `json:"M"`
+	CreationTime             int64   `json:"O"`
+	CumulativeQuoteQuantity  float64 `json:"Z,string"`
+	LastQuoteQuantity        float64 `json:"Y,string"`
+	QuoteOrderQuantity       float64 `json:"Q,string"`
+	WorkingTime              int64   `json:"W"`
+	SelfTradePreventionMode  string  `json:"V"`
+	PreventedMatchId         int64   `json:"v"`
+}
+
+// AccountPosition -- "outboundAccountPosition" event of user data stream, sent when account balance has changed.
+// Contains only assets that were changed by the event.
+type AccountPosition struct {
+	EventType      string                   `json:"e"`
+	EventTime      int64                    `json:"E"`
+	LastUpdateTime int64                    `json:"u"`
+	Balances       []AccountPositionBalance `json:"B"`
+}
+
+type AccountPositionBalance struct {
+	Asset  string  `json:"a"`
+	Free   float64 `json:"f,string"`
+	Locked float64 `json:"l,string"`
+}
+
+// UserDataStream -- connection to user data stream. Listen key is kept alive automatically until Close() is called.
+type UserDataStream struct {
+	client           *BinanceClient
+	listenKey        string
+	conn             *websocket.Conn
+	executionReports chan ExecutionReport
+	accountPositions chan AccountPosition
+	errors           chan error
+	errorsMutex      sync.Mutex
+	errorsClosed     bool
+	done             chan struct{} // Closed by Close()
+	readDone         chan struct{} // Closed when read loop stops (by Close() or because of connection failure)
+	closeOnce        sync.Once
+	closeErr         error
+}
+
+// CreateListenKey - starts a new user data stream and returns its listen key. Stream is valid for 60 minutes.
+// Details: https://github.com/binance/binance-spot-api-docs/blob/master/rest-api.md#user-data-stream-endpoints
+// Warning (if any) is returned as error and can be type-asserted to Warning.
+func (bc *BinanceClient) CreateListenKey() (string, error) {
+	type ListenKeyIntermediateFormat struct {
+		ListenKey string `json:"listenKey"`
+	}
+
+	var listenKeyTmp ListenKeyIntermediateFormat
+
+	warning, err := bc.newRequest(http.MethodPost).Do("/api/v3/userDataStream", 2, &listenKeyTmp)
+
+	if err != nil {
+		return "", err
+	}
+
+	if warning != nil {
+		return "", warning
+	}
+
+	return listenKeyTmp.ListenKey, nil
+}
+
+// KeepAliveListenKey - extends validity of listen key for 60 minutes. Binance recommends to call it every 30 minutes.
+// Warning (if any) is returned as error and can be type-asserted to Warning.
+func (bc *BinanceClient) KeepAliveListenKey(listenKey string) error {
+	return bc.listenKeyRequest(http.MethodPut, listenKey)
+}
+
+// CloseListenKey - closes user data stream.
+// Warning (if any) is returned as error and can be type-asserted to Warning.
+func (bc *BinanceClient) CloseListenKey(listenKey string) error {
+	return bc.listenKeyRequest(http.MethodDelete, listenKey)
+}
+
+func (bc *BinanceClient) listenKeyRequest(method string, listenKey string) error {
+	var emptyResponse struct{}
+
+	warning, err := bc.newRequest(method).
+		AddString("listenKey", listenKey).
+		Do("/api/v3/userDataStream", 2, &emptyResponse)
+
+	if err != nil {
+		return err
+	}
+
+	if warning != nil {
+		return warning
+	}
+
+	return nil
+}
+
+// StartUserDataStream - creates listen key, connects to user data stream and starts to decode its events.
+// Listen key is kept alive every 30 minutes in background. Events are delivered via ExecutionReports() and AccountPositions()
+// channels, errors (connection failures, failed keepalives) - via Errors() channel.
+// Call Close() when the stream is not needed anymore.
+func (bc *BinanceClient) StartUserDataStream() (*UserDataStream, error) {
+	listenKey, err := bc.CreateListenKey()
+
+	if err != nil {
+		return nil, err
+	}
+
+	conn, _, err := bc.newWebsocketDialer().Dial(bc.streamBaseURL+"/ws/"+listenKey, nil)
+
+	if err != nil {
+		_ = bc.CloseListenKey(listenKey)
+		return nil, err
+	}
+
+	stream := &UserDataStream{
+		client:           bc,
+		listenKey:        listenKey,
+		conn:             conn,
+		executionReports: make(chan ExecutionReport, userDataStreamChannelSize),
+		accountPositions: make(chan AccountPosition, userDataStreamChannelSize),
+		errors:           make(chan error, 1),
+		done:             make(chan struct{}),
+		readDone:         make(chan struct{}),
+	}
+
+	bc.lifecycle.registerStream(stream)
+
+	go stream.readLoop()
+	go stream.keepAliveLoop()
+
+	select {
+	case <-bc.lifecycle.done: // Client was closed while the stream was starting, so Close() could miss it
+		_ = stream.Close()
+		return nil, ErrClientClosed
+	default:
+	}
+
+	return stream, nil
+}
+
+// newWebsocketDialer creates websocket dialer which uses the same proxy settings as the client's HTTP transport.
+func (bc *BinanceClient) newWebsocketDialer() *websocket.Dialer {
+	return &websocket.Dialer{
+		Proxy:            bc.transport.Proxy,
+		HandshakeTimeout: 45 * time.Second,
+	}
+}
+
+// ListenKey returns listen key of the stream.
+func (s *UserDataStream) ListenKey() string {
+	return s.listenKey
+}
+
+// ExecutionReports returns channel of order updates. Channel is closed when stream stops.
+func (s *UserDataStream) ExecutionReports() <-chan ExecutionReport {
+	return s.executionReports
+}
+
+// AccountPositions returns channel of balance updates. Channel is closed when stream stops.
+func (s *UserDataStream) AccountPositions() <-chan AccountPosition {
+	return s.accountPositions
+}
+
+// Errors returns channel of stream errors. Channel is closed when stream stops.
+func (s *UserDataStream) Errors() <-chan error {
+	return s.errors
+}
+
+// Close stops keepalives, closes websocket connection and listen key. Safe to call several times.
+// Streams are closed automatically by BinanceClient.Close() too.
+func (s *UserDataStream) Close() error {
+	s.closeOnce.Do(func() {
+		defer s.client.lifecycle.unregisterStream(s)
+
+		close(s.done)
+		_ = s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		_ = s.conn.Close()
+		s.closeErr = s.client.CloseListenKey(s.listenKey)
+	})
+
+	return s.closeErr
+}
+
+func (s *UserDataStream) readLoop() {
+	defer close(s.readDone)
+	defer close(s.executionReports)
+	defer close(s.accountPositions)
+	defer s.closeErrors()
+
+	for {
+		_, message, err := s.conn.ReadMessage()
+
+		if err != nil {
+			select {
+			case <-s.done: // Connection was closed by Close(), it's not an error
+			default:
+				s.reportError(err)
+			}
+			return
+		}
+
+		var envelope struct {
+			EventType string `json:"e"`
+		}
+
+		if err := json.Unmarshal(message, &envelope); err != nil {
+			s.reportError(err)
+			continue
+		}
+
+		switch envelope.EventType {
+		case "executionReport":
+			var executionReport ExecutionReport
+			if err := json.Unmarshal(message, &executionReport); err != nil {
+				s.reportError(err)
+				continue
+			}
+			select {
+			case s.executionReports <- executionReport:
+			case <-s.done:
+				return
+			}
+
+		case "outboundAccountPosition":
+			var accountPosition AccountPosition
+			if err := json.Unmarshal(message, &accountPosition); err != nil {
+				s.reportError(err)
+				continue
+			}
+			s.client.accountCache.invalidate() // Cached balances are outdated now
+			select {
+			case s.accountPositions <- accountPosition:
+			case <-s.done:
+				return
+			}
+		}
+	}
+}
+
+func (s *UserDataStream) keepAliveLoop() {
+	ticker := time.NewTicker(listenKeyKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.readDone:
+			return
+		case <-ticker.C:
+			if err := s.client.KeepAliveListenKey(s.listenKey); err != nil {
+				s.reportError(err)
+			}
+		}
+	}
+}
+
+// reportError sends error to errors channel without blocking. If previous error was not read yet, the new one is dropped.
+func (s *UserDataStream) reportError(err error) {
+	s.errorsMutex.Lock()
+	defer s.errorsMutex.Unlock()
+
+	if s.errorsClosed {
+		return
+	}
+
+	select {
+	case s.errors <- err:
+	default:
+	}
+}
+
+func (s *UserDataStream) closeErrors() {
+	s.errorsMutex.Lock()
+	defer s.errorsMutex.Unlock()
+
+	s.errorsClosed = true
+	close(s.errors)
+}
@@ -0,0 +1,62 @@
+package bncclient
+
+import (
+	"sync"
+	"time"
+)
+
+const defaultCloudFrontBackoff = 5 * time.Minute
+const maxCloudFrontBackoff = time.Hour
+
+// cloudFrontBackoff -- escalating backoff for 403 (WAF limit violated) responses: every next 403 in a row doubles
+// recommended wait time, any other response resets it.
+type cloudFrontBackoff struct {
+	base        time.Duration
+	consecutive int // Number of 403 responses in a row
+	mutex       sync.Mutex
+}
+
+// SetCloudFrontBackoff - sets wait time recommended by Warning after the first 403 (WAF limit violated) response.
+// Every next 403 in a row doubles the wait time (up to 1 hour), any other response resets it to the initial value.
+// Default is 5 minutes. Zero or negative value restores the default.
+func (bc *BinanceClient) SetCloudFrontBackoff(backoff time.Duration) {
+	bc.cloudFrontBackoff.mutex.Lock()
+	defer bc.cloudFrontBackoff.mutex.Unlock()
+
+	if backoff <= 0 {
+		backoff = defaultCloudFrontBackoff
+	}
+
+	bc.cloudFrontBackoff.base = backoff
+}
+
+func newCloudFrontBackoff() *cloudFrontBackoff {
+	return &cloudFrontBackoff{base: defaultCloudFrontBackoff}
+}
+
+// next registers one more 403 response and returns recommended wait time.
+func (b *cloudFrontBackoff) next() time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.consecutive++
+
+	backoff := b.base
+	for i := 1; i < b.consecutive && backoff < maxCloudFrontBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > maxCloudFrontBackoff {
+		backoff = maxCloudFrontBackoff
+	}
+
+	return backoff
+}
+
+// reset is called on every response other than 403.
+func (b *cloudFrontBackoff) reset() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.consecutive = 0
+}
@@ -5,55 +5,292 @@ import (
 	"time"
 )
 
-const weightLimitPerMinute = 1200 // Current Binance weight limit per minute is 1200
-const sessionDurationMS = 60 * 1000
+const weightLimitPerMinute = 1200      // Default Binance weight limit per minute, actual one can be applied from exchangeInfo
+const sessionDurationMS = 60 * 1000    // Default duration of weight window
+const sapiWeightLimitPerMinute = 12000 // SAPI endpoints (/sapi/...) have separate IP weight limit
 
 // weightController -- "weight counter" which accumulates total weight of requests and stops polling API when weight limit is reached.
+// The window is sliding: weight of every request is remembered with its timestamp and expires windowDurationMS later,
+// so the limit is never exceeded in any span of windowDurationMS (fixed window would allow a burst of ~2x the limit
+// across the window boundary: the end of one window plus the start of the next one).
 type weightController struct {
-	lastMinuteAccumulatedWeight int
-	timestampOfZeroOutWeightMS  int64
-	mutex                       sync.Mutex
+	entries             []weightEntry // Accounted requests within the window, from the oldest to the newest
+	accumulatedWeight   int           // Total weight of entries
+	highPriorityReserve int           // Part of weightLimit available only to high priority requests
+	weightLimit         int
+	windowDurationMS    int64
+	clock               func() time.Time // Source of current time, time.Now by default (replaceable in tests)
+	mutex               sync.Mutex
+}
+
+// weightEntry -- weight accounted at the moment.
+type weightEntry struct {
+	timestampMS int64
+	weight      int
 }
 
 var wcInstance *weightController
-var lock = &sync.Mutex{}
+var wcInstanceOnce sync.Once
 
 // getWeightControllerSingleton -- constructor of weight controller. Designed as singleton.
-// TODO: Refactor according to https://medium.com/golang-issue/how-singleton-pattern-works-with-golang-2fdd61cd5a7f
+// sync.Once guarantees the instance is created exactly once and is safely visible to all goroutines.
+// All fields of the instance must be accessed only via its methods, which hold the mutex.
 func getWeightControllerSingleton() *weightController {
-	lock.Lock()
-	defer lock.Unlock()
-
-	if wcInstance == nil {
-		wcInstance = &weightController{
-			0,
-			time.Now().Unix() * 1000,
-			sync.Mutex{},
+	wcInstanceOnce.Do(func() {
+		wcInstance = newWeightController()
+	})
+	return wcInstance
+}
+
+var sapiWcInstance *weightController
+var sapiWcInstanceOnce sync.Once
+
+// getSapiWeightControllerSingleton -- weight controller of SAPI endpoints, which are accounted separately from /api endpoints.
+func getSapiWeightControllerSingleton() *weightController {
+	sapiWcInstanceOnce.Do(func() {
+		sapiWcInstance = newWeightController()
+		sapiWcInstance.setLimits(sapiWeightLimitPerMinute, sessionDurationMS)
+	})
+	return sapiWcInstance
+}
+
+// newWeightController -- creates independent weight controller (for example, for additional API key with its own limit).
+func newWeightController() *weightController {
+	return newWeightControllerWithClock(time.Now)
+}
+
+// newWeightControllerWithClock -- creates weight controller with custom source of current time, so window expiration
+// and throttling can be tested deterministically by advancing the clock manually.
+func newWeightControllerWithClock(clock func() time.Time) *weightController {
+	return &weightController{
+		weightLimit:      weightLimitPerMinute,
+		windowDurationMS: sessionDurationMS,
+		clock:            clock,
+	}
+}
+
+// nowMS -- current time of the controller's clock in milliseconds since epoch.
+func (wcInstance *weightController) nowMS() int64 {
+	return timeToMS((*wcInstance).clock())
+}
+
+func (wcInstance *weightController) getSleepTime(requestWeight int, highPriority bool) int64 {
+
+	(*wcInstance).mutex.Lock()
+	defer (*wcInstance).mutex.Unlock()
+
+	nowMS := (*wcInstance).nowMS()
+	(*wcInstance).expireEntries(nowMS)
+
+	if sleepTimeMS := (*wcInstance).waitUntilFitsMS(nowMS, (*wcInstance).accumulatedWeight, requestWeight, highPriority); sleepTimeMS > 0 {
+		return sleepTimeMS
+	}
+
+	(*wcInstance).entries = append((*wcInstance).entries, weightEntry{timestampMS: nowMS, weight: requestWeight})
+	(*wcInstance).accumulatedWeight += requestWeight
+
+	return 0
+}
+
+// peek -- side-effect-free version of getSleepTime: returns the same sleep time getSleepTime would return right now for the
+// request of given weight, but accounts nothing (and doesn't even expire old entries).
+func (wcInstance *weightController) peek(requestWeight int, highPriority bool) int64 {
+
+	(*wcInstance).mutex.Lock()
+	defer (*wcInstance).mutex.Unlock()
+
+	nowMS := (*wcInstance).nowMS()
+
+	return (*wcInstance).waitUntilFitsMS(nowMS, (*wcInstance).weightWithinWindow(nowMS), requestWeight, highPriority)
+}
+
+// waitUntilFitsMS -- time (ms) until request of given weight fits into the lane limit together with weight already
+// accounted in the window (0 if it fits right now). Request heavier than the whole limit fits only into empty window,
+// otherwise it would never be made. MUST be called with the mutex held.
+func (wcInstance *weightController) waitUntilFitsMS(nowMS int64, weightInWindow int, requestWeight int, highPriority bool) int64 {
+	limit := (*wcInstance).laneLimit(highPriority)
+
+	if limit <= 0 { // All of the limit is reserved for high priority requests
+		return (*wcInstance).windowDurationMS
+	}
+
+	if weightInWindow+requestWeight <= limit || weightInWindow == 0 {
+		return 0
+	}
+
+	if requestWeight > limit {
+		return (*wcInstance).waitUntilBelowMS(nowMS, 1)
+	}
+
+	return (*wcInstance).waitUntilBelowMS(nowMS, limit-requestWeight+1)
+}
+
+// expireEntries -- forgets weight of requests made earlier than windowDurationMS ago. MUST be called with the mutex held.
+func (wcInstance *weightController) expireEntries(nowMS int64) {
+	expired := 0
+	for expired < len((*wcInstance).entries) && (*wcInstance).isExpired((*wcInstance).entries[expired], nowMS) {
+		(*wcInstance).accumulatedWeight -= (*wcInstance).entries[expired].weight
+		expired++
+	}
+
+	if expired > 0 {
+		(*wcInstance).entries = append([]weightEntry(nil), (*wcInstance).entries[expired:]...)
+	}
+}
+
+// weightWithinWindow -- total weight of not expired entries, without modifying them. MUST be called with the mutex held.
+func (wcInstance *weightController) weightWithinWindow(nowMS int64) int {
+	weight := (*wcInstance).accumulatedWeight
+	for _, entry := range (*wcInstance).entries {
+		if !(*wcInstance).isExpired(entry, nowMS) {
+			break
 		}
+		weight -= entry.weight
 	}
-	return wcInstance
+
+	return weight
 }
 
-func (wcInstance *weightController) getSleepTime(requestWeight int) int64 {
+// waitUntilBelowMS -- time (ms) until enough entries expire, so accumulated weight drops below the limit.
+// MUST be called with the mutex held.
+func (wcInstance *weightController) waitUntilBelowMS(nowMS int64, limit int) int64 {
+	weight := (*wcInstance).weightWithinWindow(nowMS)
+
+	for _, entry := range (*wcInstance).entries {
+		if (*wcInstance).isExpired(entry, nowMS) {
+			continue
+		}
+
+		weight -= entry.weight
+		if weight < limit {
+			return entry.timestampMS + (*wcInstance).windowDurationMS - nowMS
+		}
+	}
+
+	return (*wcInstance).windowDurationMS // Limit is not positive (all of it is reserved for high priority requests)
+}
+
+func (wcInstance *weightController) isExpired(entry weightEntry, nowMS int64) bool {
+	return nowMS-entry.timestampMS >= (*wcInstance).windowDurationMS
+}
+
+// laneLimit -- weight limit for requests of given priority: normal priority requests can't use weight reserved
+// for high priority ones. MUST be called with the mutex held.
+func (wcInstance *weightController) laneLimit(highPriority bool) int {
+	if highPriority {
+		return (*wcInstance).weightLimit
+	}
+
+	return (*wcInstance).weightLimit - (*wcInstance).highPriorityReserve
+}
+
+// setHighPriorityReserve -- sets part of weight limit available only to high priority requests.
+func (wcInstance *weightController) setHighPriorityReserve(weight int) {
+
+	(*wcInstance).mutex.Lock()
+	defer (*wcInstance).mutex.Unlock()
+
+	(*wcInstance).highPriorityReserve = weight
+}
+
+// currentTimestampMS -- current time in milliseconds since epoch.
+func currentTimestampMS() int64 {
+	return timeToMS(time.Now())
+}
+
+func timeToMS(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// weightReservation -- weight reserved in advance by one caller for a batch of requests (see reserve). Only requests
+// made with the reservation consume it, so concurrent callers can't use weight reserved by somebody else.
+// The reservation expires together with its entry of the window.
+type weightReservation struct {
+	controller   *weightController
+	remaining    int   // Reserved weight not consumed yet, guarded by the controller's mutex
+	reservedAtMS int64 // When the reservation was made
+}
+
+// reserve -- atomically checks if totalWeight of the whole batch of requests fits into the current window.
+// If it fits, totalWeight is accounted at once and returned reservation is consumed by requests of the batch instead of
+// accumulating weight again (see consume). If it doesn't fit, nothing is accounted, and returned value is the time (ms)
+// to wait until it fits. Batch heavier than the lane limit never fits, so it can't be reserved at all: nil reservation
+// and 0 are returned, and requests of such batch should be accounted one by one as usual.
+func (wcInstance *weightController) reserve(totalWeight int, highPriority bool) (*weightReservation, int64) {
 
 	(*wcInstance).mutex.Lock()
 	defer (*wcInstance).mutex.Unlock()
 
-	currentTimestampMS := time.Now().Unix() * 1000
-	elapsedTimeMS := currentTimestampMS - (*wcInstance).timestampOfZeroOutWeightMS
-	recommendedSleepTime := int64(0)
+	if totalWeight > (*wcInstance).laneLimit(highPriority) {
+		return nil, 0
+	}
+
+	nowMS := (*wcInstance).nowMS()
+	(*wcInstance).expireEntries(nowMS)
+
+	if sleepTimeMS := (*wcInstance).waitUntilFitsMS(nowMS, (*wcInstance).accumulatedWeight, totalWeight, highPriority); sleepTimeMS > 0 {
+		return nil, sleepTimeMS
+	}
+
+	(*wcInstance).entries = append((*wcInstance).entries, weightEntry{timestampMS: nowMS, weight: totalWeight})
+	(*wcInstance).accumulatedWeight += totalWeight
+
+	return &weightReservation{controller: wcInstance, remaining: totalWeight, reservedAtMS: nowMS}, 0
+}
 
-	if (*wcInstance).lastMinuteAccumulatedWeight < weightLimitPerMinute && elapsedTimeMS <= sessionDurationMS {
-		(*wcInstance).lastMinuteAccumulatedWeight += requestWeight
-		//fmt.Printf("Accumulated Weight for current min [%s]: %d\n", time.Now().Format("15:04:05"), (*wcInstance).lastMinuteAccumulatedWeight)
-	} else if (*wcInstance).lastMinuteAccumulatedWeight >= weightLimitPerMinute && elapsedTimeMS <= sessionDurationMS {
-		recommendedSleepTime = sessionDurationMS - elapsedTimeMS
-		//fmt.Printf("Accumulated Weight for current min [%s] is FULL: %d, recommended sleep time: %dsec\n", time.Now().Format("15:04:05"), (*wcInstance).lastMinuteAccumulatedWeight, recommendedSleepTime/1000)
-	} else { // If elapsed time > 1min
-		(*wcInstance).lastMinuteAccumulatedWeight = requestWeight
-		(*wcInstance).timestampOfZeroOutWeightMS = currentTimestampMS
-		//fmt.Printf("NEW 1-MIN REQUEST SESSION STARTED.\n")
+// consume -- takes weight of the request from the reservation. Returns false if the reservation is nil, expired or
+// doesn't have enough weight left, then the request should be accounted in weight controller as usual.
+func (reservation *weightReservation) consume(requestWeight int) bool {
+	if reservation == nil {
+		return false
 	}
 
-	return recommendedSleepTime
-}
\ No newline at end of file
+	wcInstance := reservation.controller
+
+	(*wcInstance).mutex.Lock()
+	defer (*wcInstance).mutex.Unlock()
+
+	if (*wcInstance).nowMS()-reservation.reservedAtMS >= (*wcInstance).windowDurationMS || reservation.remaining < requestWeight {
+		return false
+	}
+
+	reservation.remaining -= requestWeight
+
+	return true
+}
+
+// setLimits -- replaces weight limit and window duration (for example, with actual values from exchangeInfo).
+func (wcInstance *weightController) setLimits(weightLimit int, windowDurationMS int64) {
+
+	(*wcInstance).mutex.Lock()
+	defer (*wcInstance).mutex.Unlock()
+
+	(*wcInstance).weightLimit = weightLimit
+	(*wcInstance).windowDurationMS = windowDurationMS
+}
+
+// usage -- returns weight accumulated in the current (sliding) window and the weight limit.
+func (wcInstance *weightController) usage() (int, int) {
+
+	(*wcInstance).mutex.Lock()
+	defer (*wcInstance).mutex.Unlock()
+
+	return (*wcInstance).weightWithinWindow((*wcInstance).nowMS()), (*wcInstance).weightLimit
+}
+
+// remainingWindowMS -- returns time (ms) left until the oldest accounted weight expires (0 if nothing is accounted).
+func (wcInstance *weightController) remainingWindowMS() int64 {
+
+	(*wcInstance).mutex.Lock()
+	defer (*wcInstance).mutex.Unlock()
+
+	nowMS := (*wcInstance).nowMS()
+
+	for _, entry := range (*wcInstance).entries {
+		if !(*wcInstance).isExpired(entry, nowMS) {
+			return entry.timestampMS + (*wcInstance).windowDurationMS - nowMS
+		}
+	}
+
+	return 0
+}
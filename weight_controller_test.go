@@ -0,0 +1,312 @@
+package bncclient
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// manualClock -- clock for weight controller tests, which moves only when advanced.
+type manualClock struct {
+	now time.Time
+}
+
+func newManualClock() *manualClock {
+	return &manualClock{now: time.Unix(1700000000, 0)}
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
+
+func (c *manualClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestWeightControllerBurstNeverExceedsLimit(t *testing.T) {
+	clock := newManualClock()
+	wc := newWeightControllerWithClock(clock.Now)
+
+	for i := 0; i < weightLimitPerMinute-1; i++ {
+		if sleepTimeMS := wc.getSleepTime(1, false); sleepTimeMS != 0 {
+			t.Fatalf("request %d: expected no sleep, got %dms", i, sleepTimeMS)
+		}
+	}
+
+	// 1199/1200 is accounted, so request of weight 50 doesn't fit:
+	if sleepTimeMS := wc.getSleepTime(50, false); sleepTimeMS <= 0 {
+		t.Fatalf("expected sleep for request which exceeds the limit, got %dms", sleepTimeMS)
+	}
+
+	if used, _ := wc.usage(); used != weightLimitPerMinute-1 {
+		t.Fatalf("rejected request must not be accounted: used %d", used)
+	}
+
+	// But request of weight 1 still fits exactly into the limit:
+	if sleepTimeMS := wc.getSleepTime(1, false); sleepTimeMS != 0 {
+		t.Fatalf("expected request to fit exactly into the limit, got sleep %dms", sleepTimeMS)
+	}
+
+	if used, limit := wc.usage(); used > limit {
+		t.Fatalf("accounted weight %d exceeds the limit %d", used, limit)
+	}
+}
+
+func TestWeightControllerBurstAcrossWindow(t *testing.T) {
+	clock := newManualClock()
+	wc := newWeightControllerWithClock(clock.Now)
+
+	sentAtMS := make([]int64, 0)
+	weights := make([]int, 0)
+
+	// Try to send requests of weight 50 every second for 3 minutes, and check every span of 60s:
+	for i := 0; i < 180; i++ {
+		if wc.getSleepTime(50, false) == 0 {
+			sentAtMS = append(sentAtMS, timeToMS(clock.Now()))
+			weights = append(weights, 50)
+		}
+		clock.Advance(time.Second)
+	}
+
+	for i := range sentAtMS {
+		total := 0
+		for j := i; j < len(sentAtMS) && sentAtMS[j]-sentAtMS[i] < sessionDurationMS; j++ {
+			total += weights[j]
+		}
+
+		if total > weightLimitPerMinute {
+			t.Fatalf("%d weight sent within 60s starting at request %d, limit is %d", total, i, weightLimitPerMinute)
+		}
+	}
+}
+
+func TestWeightControllerReservationBelongsToCaller(t *testing.T) {
+	clock := newManualClock()
+	wc := newWeightControllerWithClock(clock.Now)
+
+	reservation, sleepTimeMS := wc.reserve(1000, false)
+	if reservation == nil || sleepTimeMS != 0 {
+		t.Fatalf("expected reservation to be made, got sleep %dms", sleepTimeMS)
+	}
+
+	// Another caller doesn't consume the reservation, so only 200 is left for it:
+	if sleepTimeMS := wc.getSleepTime(300, false); sleepTimeMS <= 0 {
+		t.Fatalf("expected reserved weight to be unavailable for other callers")
+	}
+
+	if _, sleepTimeMS := wc.reserve(300, false); sleepTimeMS <= 0 {
+		t.Fatalf("expected second reservation not to fit")
+	}
+
+	for i := 0; i < 10; i++ {
+		if !reservation.consume(100) {
+			t.Fatalf("request %d of the batch should consume the reservation", i)
+		}
+	}
+
+	if reservation.consume(1) {
+		t.Fatalf("exhausted reservation must not be consumed")
+	}
+
+	if used, _ := wc.usage(); used != 1000 {
+		t.Fatalf("consumed reservation must not be accounted again: used %d", used)
+	}
+}
+
+func TestWeightControllerReservationExpiresWithItsEntry(t *testing.T) {
+	clock := newManualClock()
+	wc := newWeightControllerWithClock(clock.Now)
+
+	first, _ := wc.reserve(100, false)
+	clock.Advance(30 * time.Second)
+	second, _ := wc.reserve(100, false)
+	clock.Advance(30 * time.Second)
+
+	// The second reservation doesn't extend life of the first one:
+	if first.consume(1) {
+		t.Fatalf("expected the first reservation to expire after the window")
+	}
+
+	if !second.consume(1) {
+		t.Fatalf("expected the second reservation to be still valid")
+	}
+
+	if used, _ := wc.usage(); used != 100 {
+		t.Fatalf("expected only weight of the second reservation in the window, got %d", used)
+	}
+}
+
+func TestWeightControllerReservationTooHeavy(t *testing.T) {
+	wc := newWeightControllerWithClock(newManualClock().Now)
+
+	reservation, sleepTimeMS := wc.reserve(weightLimitPerMinute+1, false)
+	if reservation != nil || sleepTimeMS != 0 {
+		t.Fatalf("batch heavier than the limit must not be reserved, got sleep %dms", sleepTimeMS)
+	}
+
+	if reservation.consume(1) {
+		t.Fatalf("nil reservation must not be consumed")
+	}
+}
+
+func TestWeightControllerThrottlesAtLimit(t *testing.T) {
+	clock := newManualClock()
+	wc := newWeightControllerWithClock(clock.Now)
+
+	if sleepTimeMS := wc.getSleepTime(weightLimitPerMinute, false); sleepTimeMS != 0 {
+		t.Fatalf("expected request of exactly the limit to fit into empty window, got sleep %dms", sleepTimeMS)
+	}
+
+	clock.Advance(15 * time.Second)
+
+	// The whole limit is used, so the next request waits until the first one expires (60s after it was made):
+	if sleepTimeMS := wc.getSleepTime(1, false); sleepTimeMS != 45*1000 {
+		t.Fatalf("expected sleep 45000ms, got %dms", sleepTimeMS)
+	}
+
+	if sleepTimeMS := wc.peek(1, false); sleepTimeMS != 45*1000 {
+		t.Fatalf("expected peek to agree with getSleepTime, got %dms", sleepTimeMS)
+	}
+}
+
+func TestWeightControllerResetsAfterWindow(t *testing.T) {
+	clock := newManualClock()
+	wc := newWeightControllerWithClock(clock.Now)
+
+	wc.getSleepTime(weightLimitPerMinute, false)
+	clock.Advance(60 * time.Second)
+
+	if used, _ := wc.usage(); used != 0 {
+		t.Fatalf("expected empty window 60s after the request, got %d", used)
+	}
+
+	if sleepTimeMS := wc.getSleepTime(weightLimitPerMinute, false); sleepTimeMS != 0 {
+		t.Fatalf("expected the whole limit to be available again, got sleep %dms", sleepTimeMS)
+	}
+
+	if remainingMS := wc.remainingWindowMS(); remainingMS != sessionDurationMS {
+		t.Fatalf("expected the window to start over, got %dms remaining", remainingMS)
+	}
+}
+
+func TestWeightControllerWindowBoundary(t *testing.T) {
+	clock := newManualClock()
+	wc := newWeightControllerWithClock(clock.Now)
+
+	wc.getSleepTime(weightLimitPerMinute, false)
+
+	// 1ms before the boundary the weight is still accounted:
+	clock.Advance(60*time.Second - time.Millisecond)
+
+	if sleepTimeMS := wc.getSleepTime(1, false); sleepTimeMS != 1 {
+		t.Fatalf("expected sleep 1ms right before the boundary, got %dms", sleepTimeMS)
+	}
+
+	// Exactly at the boundary it expires:
+	clock.Advance(time.Millisecond)
+
+	if sleepTimeMS := wc.getSleepTime(1, false); sleepTimeMS != 0 {
+		t.Fatalf("expected the request to fit exactly at the boundary, got sleep %dms", sleepTimeMS)
+	}
+}
+
+func TestWeightControllerHighPriorityReserve(t *testing.T) {
+	clock := newManualClock()
+	wc := newWeightControllerWithClock(clock.Now)
+	wc.setHighPriorityReserve(200)
+
+	if sleepTimeMS := wc.getSleepTime(weightLimitPerMinute-200, false); sleepTimeMS != 0 {
+		t.Fatalf("expected normal priority request to fit into its lane, got sleep %dms", sleepTimeMS)
+	}
+
+	if sleepTimeMS := wc.getSleepTime(1, false); sleepTimeMS <= 0 {
+		t.Fatalf("expected normal priority request not to use the reserve")
+	}
+
+	if sleepTimeMS := wc.getSleepTime(200, true); sleepTimeMS != 0 {
+		t.Fatalf("expected high priority request to use the reserve, got sleep %dms", sleepTimeMS)
+	}
+}
+
+// Run with -race: concurrent accounting, peeking and reading of usage must not race.
+func TestWeightControllerConcurrentAccess(t *testing.T) {
+	wc := newWeightController()
+	wc.setLimits(1000000, sessionDurationMS)
+
+	const goroutines = 50
+	const requestsPerGoroutine = 200
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(highPriority bool) {
+			defer wg.Done()
+			for j := 0; j < requestsPerGoroutine; j++ {
+				wc.getSleepTime(1, highPriority)
+				wc.peek(1, highPriority)
+				wc.usage()
+				wc.remainingWindowMS()
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+
+	if used, _ := wc.usage(); used != goroutines*requestsPerGoroutine {
+		t.Fatalf("expected %d accounted weight, got %d", goroutines*requestsPerGoroutine, used)
+	}
+}
+
+func TestWeightControllerSingletonConcurrentInit(t *testing.T) {
+	const goroutines = 50
+
+	instances := make(chan *weightController, goroutines)
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			instances <- getWeightControllerSingleton()
+		}()
+	}
+	wg.Wait()
+	close(instances)
+
+	first := <-instances
+	for instance := range instances {
+		if instance != first {
+			t.Fatal("expected the same instance for all goroutines")
+		}
+	}
+}
+
+func TestWeightControllerConcurrentBoundaryCrossing(t *testing.T) {
+	clock := newManualClock()
+	wc := newWeightControllerWithClock(clock.Now)
+
+	wc.getSleepTime(weightLimitPerMinute, false)
+	clock.Advance(60 * time.Second) // All goroutines below see the previous window expired
+
+	const goroutines = 100
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(weight int) {
+			defer wg.Done()
+			if sleepTimeMS := wc.getSleepTime(weight, false); sleepTimeMS != 0 {
+				t.Errorf("weight %d: expected no sleep, got %dms", weight, sleepTimeMS)
+			}
+		}(i%10 + 1)
+	}
+	wg.Wait()
+
+	expected := 0
+	for i := 0; i < goroutines; i++ {
+		expected += i%10 + 1
+	}
+
+	if used, _ := wc.usage(); used != expected {
+		t.Fatalf("expected %d accounted after the boundary (sum of all weights), got %d", expected, used)
+	}
+}